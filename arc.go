@@ -0,0 +1,186 @@
+package gg
+
+import "math"
+
+// ellipticalArc appends an elliptical arc centered at (x, y), whose x axis is
+// rotated by rotation radians, approximated by n quadratic segments.
+func (dc *Context) ellipticalArc(x, y, rx, ry, rotation, angle1, angle2 float64, n int) {
+	sr, cr := math.Sincos(rotation)
+	at := func(a float64) (float64, float64) {
+		ex, ey := rx*math.Cos(a), ry*math.Sin(a)
+		return x + ex*cr - ey*sr, y + ex*sr + ey*cr
+	}
+	for i := range n {
+		p1 := float64(i+0) / float64(n)
+		p2 := float64(i+1) / float64(n)
+		a1 := angle1 + (angle2-angle1)*p1
+		a2 := angle1 + (angle2-angle1)*p2
+		x0, y0 := at(a1)
+		x1, y1 := at((a1 + a2) / 2)
+		x2, y2 := at(a2)
+		cx := 2*x1 - x0/2 - x2/2
+		cy := 2*y1 - y0/2 - y2/2
+		if i == 0 {
+			if dc.hasCurrent {
+				dc.LineTo(x0, y0)
+			} else {
+				dc.MoveTo(x0, y0)
+			}
+		}
+		dc.QuadraticTo(cx, cy, x2, y2)
+	}
+}
+
+// arcSegments returns the number of quadratic segments used for an arc
+// spanning the given angle, so that a full turn uses 16 segments.
+func arcSegments(angle float64) int {
+	return max(int(math.Ceil(math.Abs(angle)/(math.Pi/8)-1e-9)), 1)
+}
+
+// currentUserPoint returns the current point in user space, i.e. before the
+// current transformation matrix is applied.
+func (dc *Context) currentUserPoint() (Point, bool) {
+	if !dc.hasCurrent {
+		return Point{}, false
+	}
+	inv, ok := dc.matrix.Invert()
+	if !ok {
+		return Point{}, false
+	}
+	x, y := inv.TransformPoint(dc.current.X, dc.current.Y)
+	return Point{x, y}, true
+}
+
+// DrawRotatedEllipticalArc draws an elliptical arc centered at (x, y) whose
+// x axis is rotated by rotation radians. Angles are measured on the
+// unrotated ellipse.
+//
+// DrawRotatedEllipticalArc 绘制以 (x, y) 为中心、x 轴旋转 rotation 弧度的椭圆弧。
+// 角度基于未旋转的椭圆计算。
+func (dc *Context) DrawRotatedEllipticalArc(x, y, rx, ry, rotation, angle1, angle2 float64) {
+	dc.ellipticalArc(x, y, rx, ry, rotation, angle1, angle2, arcSegments(angle2-angle1))
+}
+
+// DrawRotatedEllipse draws an ellipse centered at (x, y) whose x axis is
+// rotated by rotation radians.
+//
+// DrawRotatedEllipse 绘制以 (x, y) 为中心、x 轴旋转 rotation 弧度的椭圆。
+func (dc *Context) DrawRotatedEllipse(x, y, rx, ry, rotation float64) {
+	dc.NewSubPath()
+	dc.DrawRotatedEllipticalArc(x, y, rx, ry, rotation, 0, 2*math.Pi)
+	dc.ClosePath()
+}
+
+// ArcTo adds a circular arc of radius r to the current path, tangent to the
+// line from the current point to (x1, y1) and to the line from (x1, y1) to
+// (x2, y2), like the HTML canvas arcTo. A straight line connects the current
+// point to the start of the arc. If there is no current point, it first
+// performs MoveTo(x1, y1). Degenerate cases fall back to LineTo(x1, y1).
+//
+// ArcTo 向当前路径添加半径为 r 的圆弧，该圆弧与当前点到 (x1, y1) 的直线
+// 以及 (x1, y1) 到 (x2, y2) 的直线相切，与 HTML canvas 的 arcTo 一致。
+// 当前点与圆弧起点之间以直线连接。如果没有当前点，则首先执行 MoveTo(x1, y1)。
+// 退化情况下等价于 LineTo(x1, y1)。
+func (dc *Context) ArcTo(x1, y1, x2, y2, r float64) {
+	p0, ok := dc.currentUserPoint()
+	if !ok {
+		if !dc.hasCurrent {
+			dc.MoveTo(x1, y1)
+		} else {
+			dc.LineTo(x1, y1)
+		}
+		return
+	}
+	// unit vectors from the corner towards both ends
+	ux, uy := p0.X-x1, p0.Y-y1
+	vx, vy := x2-x1, y2-y1
+	lu, lv := math.Hypot(ux, uy), math.Hypot(vx, vy)
+	if r <= 0 || lu == 0 || lv == 0 {
+		dc.LineTo(x1, y1)
+		return
+	}
+	ux, uy = ux/lu, uy/lu
+	vx, vy = vx/lv, vy/lv
+	cross := ux*vy - uy*vx
+	if math.Abs(cross) < 1e-12 {
+		dc.LineTo(x1, y1)
+		return
+	}
+	// half of the angle between both lines
+	theta := math.Acos(max(-1, min(1, ux*vx+uy*vy))) / 2
+	d := r / math.Tan(theta) // distance from the corner to the tangent points
+	h := r / math.Sin(theta) // distance from the corner to the center
+	bx, by := ux+vx, uy+vy
+	lb := math.Hypot(bx, by)
+	cx, cy := x1+bx/lb*h, y1+by/lb*h
+	t1x, t1y := x1+ux*d, y1+uy*d
+	t2x, t2y := x1+vx*d, y1+vy*d
+	a1 := math.Atan2(t1y-cy, t1x-cx)
+	a2 := math.Atan2(t2y-cy, t2x-cx)
+	delta := math.Remainder(a2-a1, 2*math.Pi)
+	dc.ellipticalArc(cx, cy, r, r, 0, a1, a1+delta, arcSegments(delta))
+}
+
+// SVGArcTo adds an elliptical arc from the current point to (x, y), using
+// the endpoint parameterization of the SVG path "A" command. rotation is the
+// rotation of the ellipse x axis in radians, largeArc selects the arc longer
+// than 180 degrees and sweep selects the arc drawn in the positive-angle
+// direction. Radii that are too small are scaled up as per the SVG
+// specification. If there is no current point, it is equivalent to
+// MoveTo(x, y).
+//
+// SVGArcTo 使用 SVG 路径 "A" 命令的端点参数，从当前点向 (x, y) 添加椭圆弧。
+// rotation 为椭圆 x 轴的旋转弧度，largeArc 选择大于 180 度的弧，
+// sweep 选择沿正角度方向绘制的弧。过小的半径会按 SVG 规范放大。
+// 如果没有当前点，则等价于 MoveTo(x, y)。
+func (dc *Context) SVGArcTo(rx, ry, rotation float64, largeArc, sweep bool, x, y float64) {
+	p0, ok := dc.currentUserPoint()
+	if !ok {
+		if !dc.hasCurrent {
+			dc.MoveTo(x, y)
+		} else {
+			dc.LineTo(x, y)
+		}
+		return
+	}
+	if p0.X == x && p0.Y == y {
+		return
+	}
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		dc.LineTo(x, y)
+		return
+	}
+	// step 1: compute (x1', y1')
+	sr, cr := math.Sincos(rotation)
+	dx2, dy2 := (p0.X-x)/2, (p0.Y-y)/2
+	x1p := cr*dx2 + sr*dy2
+	y1p := -sr*dx2 + cr*dy2
+	// ensure radii are large enough
+	if l := sq(x1p)/sq(rx) + sq(y1p)/sq(ry); l > 1 {
+		s := math.Sqrt(l)
+		rx, ry = rx*s, ry*s
+	}
+	// step 2: compute (cx', cy')
+	num := sq(rx)*sq(ry) - sq(rx)*sq(y1p) - sq(ry)*sq(x1p)
+	den := sq(rx)*sq(y1p) + sq(ry)*sq(x1p)
+	coef := math.Sqrt(max(num/den, 0))
+	if largeArc == sweep {
+		coef = -coef
+	}
+	cxp := coef * rx * y1p / ry
+	cyp := -coef * ry * x1p / rx
+	// step 3: compute (cx, cy) from (cx', cy')
+	cx := cr*cxp - sr*cyp + (p0.X+x)/2
+	cy := sr*cxp + cr*cyp + (p0.Y+y)/2
+	// step 4: compute the start angle and the sweep
+	a1 := math.Atan2((y1p-cyp)/ry, (x1p-cxp)/rx)
+	a2 := math.Atan2((-y1p-cyp)/ry, (-x1p-cxp)/rx)
+	delta := math.Mod(a2-a1, 2*math.Pi)
+	if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	} else if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	}
+	dc.ellipticalArc(cx, cy, rx, ry, rotation, a1, a1+delta, arcSegments(delta))
+}
@@ -0,0 +1,72 @@
+package gg
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMatrixInvert(t *testing.T) {
+	m := Identity().Scale(2, 3).Rotate(0.7).Translate(10, -4).Shear(0.2, 0.1)
+	inv, ok := m.Invert()
+	if !ok {
+		t.Fatal("matrix should be invertible")
+	}
+	x, y := m.TransformPoint(12.5, -7.25)
+	x, y = inv.TransformPoint(x, y)
+	if math.Abs(x-12.5) > 1e-9 || math.Abs(y+7.25) > 1e-9 {
+		t.Fatalf("expected (12.5, -7.25), got (%v, %v)", x, y)
+	}
+	if _, ok := Scale(0, 1).Invert(); ok {
+		t.Fatal("singular matrix should not be invertible")
+	}
+}
+
+func TestArcToEndsOnTangent(t *testing.T) {
+	dc := NewContext(100, 100)
+	dc.MoveTo(10, 10)
+	dc.ArcTo(90, 10, 90, 90, 20)
+	p, ok := dc.GetCurrentPoint()
+	if !ok {
+		t.Fatal("expected a current point")
+	}
+	// the arc ends on the second tangent, 20px below the corner
+	if math.Abs(p.X-90) > 0.01 || math.Abs(p.Y-30) > 0.01 {
+		t.Fatalf("expected (90, 30), got %v", p)
+	}
+}
+
+func TestSVGArcToEndsOnTarget(t *testing.T) {
+	dc := NewContext(100, 100)
+	dc.Translate(5, 5)
+	dc.Rotate(0.3)
+	for _, flags := range [][2]bool{{false, false}, {false, true}, {true, false}, {true, true}} {
+		dc.MoveTo(20, 50)
+		dc.SVGArcTo(30, 15, Radians(30), flags[0], flags[1], 70, 40)
+		p, _ := dc.GetCurrentPoint()
+		x, y := dc.TransformPoint(70, 40)
+		if math.Abs(p.X-x) > 0.01 || math.Abs(p.Y-y) > 0.01 {
+			t.Fatalf("flags %v: expected (%v, %v), got %v", flags, x, y, p)
+		}
+		dc.ClearPath()
+	}
+}
+
+func TestArcs(t *testing.T) {
+	dc := NewContext(100, 100)
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+	dc.MoveTo(10, 90)
+	dc.ArcTo(10, 10, 90, 10, 25)
+	dc.LineTo(90, 10)
+	dc.MoveTo(20, 80)
+	dc.SVGArcTo(30, 20, Radians(-45), true, false, 80, 60)
+	dc.DrawRotatedEllipse(50, 50, 30, 10, Radians(60))
+	dc.SetRGB(0, 0, 0)
+	dc.SetLineWidth(2)
+	if err := dc.Stroke(); err != nil {
+		t.Fatal(err)
+	}
+	if err := saveImage(dc, "TestArcs"); err != nil {
+		t.Fatal(err)
+	}
+}
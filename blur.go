@@ -0,0 +1,226 @@
+package gg
+
+import (
+	"image"
+	"math"
+
+	"github.com/FloatTech/gg/internal/pixop"
+)
+
+// Blur returns a filter that applies a Gaussian blur with the standard
+// deviation sigma to the image. It is approximated by three box blurs over
+// the premultiplied image, so transparent pixels do not darken their
+// neighbours. Pixels beyond the edges repeat the edge pixels.
+//
+// Blur 返回对图像应用标准差为 sigma 的高斯模糊的滤镜。
+// 它通过对预乘图像进行三次盒式模糊来近似，因此透明像素不会使相邻像素变暗。
+// 边缘之外的像素重复边缘像素。
+func Blur(sigma float64) Filter {
+	return BlurXY(sigma, sigma)
+}
+
+// BlurXY returns a filter that applies a Gaussian blur with the standard
+// deviations sigmaX and sigmaY along the axes, like Blur.
+//
+// BlurXY 返回沿两个坐标轴分别以标准差 sigmaX 和 sigmaY 应用高斯模糊的滤镜，与 Blur 相同。
+func BlurXY(sigmaX, sigmaY float64) Filter {
+	return FilterFunc(func(src *image.NRGBA) *image.NRGBA {
+		return blurXY(src, sigmaX, sigmaY, Workers())
+	})
+}
+
+// blurXY is the filter of BlurXY on the given number of goroutines.
+func blurXY(src *image.NRGBA, sigmaX, sigmaY float64, workers int) *image.NRGBA {
+	p := premultipliedPix(src)
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	for _, r := range gaussBoxes(sigmaX) {
+		p = boxBlur(p, w, h, r, 0, workers)
+	}
+	for _, r := range gaussBoxes(sigmaY) {
+		p = boxBlur(p, w, h, 0, r, workers)
+	}
+	return fromPremultipliedPix(p, src.Rect)
+}
+
+// BoxBlur returns a filter that averages every pixel with its
+// (2rx+1)x(2ry+1) neighbourhood, like Blur.
+//
+// BoxBlur 返回将每个像素与其 (2rx+1)x(2ry+1) 邻域取平均的滤镜，与 Blur 相同。
+func BoxBlur(rx, ry int) Filter {
+	return FilterFunc(func(src *image.NRGBA) *image.NRGBA {
+		p := premultipliedPix(src)
+		w, h := src.Rect.Dx(), src.Rect.Dy()
+		return fromPremultipliedPix(boxBlur(p, w, h, max(rx, 0), max(ry, 0), Workers()), src.Rect)
+	})
+}
+
+// gaussBoxes returns the radii of the three box blurs that approximate a
+// Gaussian blur with the standard deviation sigma, see
+// http://blog.ivank.net/fastest-gaussian-blur.html
+func gaussBoxes(sigma float64) []int {
+	const n = 3
+	if sigma <= 0 {
+		return nil
+	}
+	wl := int(math.Sqrt(12*sigma*sigma/n + 1))
+	if wl%2 == 0 {
+		wl--
+	}
+	m := int(math.Round((12*sigma*sigma - n*float64(wl*wl) - 4*n*float64(wl) - 3*n) / float64(-4*wl-4)))
+	radii := make([]int, n)
+	for i := range radii {
+		size := wl
+		if i >= m {
+			size += 2
+		}
+		radii[i] = size / 2
+	}
+	return radii
+}
+
+// boxBlur returns the premultiplied pixels p of a w x h image averaged
+// over (2rx+1)x(2ry+1) boxes, one axis after the other, on the given
+// number of goroutines.
+func boxBlur(p []float64, w, h, rx, ry, workers int) []float64 {
+	if rx > 0 {
+		out := make([]float64, len(p))
+		pixop.Rows(workers, h, func(y0, y1 int) {
+			for y := y0; y < y1; y++ {
+				boxLine(out, p, 4*y*w, 4, w, rx)
+			}
+		})
+		p = out
+	}
+	if ry > 0 {
+		out := make([]float64, len(p))
+		pixop.Rows(workers, w, func(x0, x1 int) {
+			for x := x0; x < x1; x++ {
+				boxLine(out, p, 4*x, 4*w, h, ry)
+			}
+		})
+		p = out
+	}
+	return p
+}
+
+// boxLine averages the n pixels of src starting at i0 and step apart over
+// windows of 2r+1 pixels into dst, repeating the pixels at both ends.
+func boxLine(dst, src []float64, i0, step, n, r int) {
+	at := func(k int) int { return i0 + max(0, min(n-1, k))*step }
+	var sum [4]float64
+	for k := -r; k <= r; k++ {
+		i := at(k)
+		for c := range sum {
+			sum[c] += src[i+c]
+		}
+	}
+	scale := 1 / float64(2*r+1)
+	for k := range n {
+		i := i0 + k*step
+		for c := range sum {
+			dst[i+c] = sum[c] * scale
+		}
+		in, out := at(k+r+1), at(k-r)
+		for c := range sum {
+			sum[c] += src[in+c] - src[out+c]
+		}
+	}
+}
+
+// MotionBlur returns a filter that blurs the image along a line of the
+// given length in pixels, at angle in degrees, like a fast moving camera.
+//
+// MotionBlur 返回沿给定长度（像素）、角度（度）的直线模糊图像的滤镜，类似快速移动的相机。
+func MotionBlur(length, angle float64) Filter {
+	sin, cos := math.Sincos(Radians(angle))
+	n := max(1, int(math.Ceil(length))+1)
+	return sampleBlur(func(x, y float64, add func(x, y float64)) {
+		for k := range n {
+			t := length * (float64(k)/float64(max(n-1, 1)) - 0.5)
+			add(x+t*cos, y+t*sin)
+		}
+	})
+}
+
+// RadialBlur returns a filter that blurs the image along circles around
+// (cx, cy), as if it spun by angle degrees.
+//
+// RadialBlur 返回沿以 (cx, cy) 为圆心的圆模糊图像的滤镜，如同图像旋转了 angle 度。
+func RadialBlur(cx, cy, angle float64) Filter {
+	a := Radians(angle)
+	return sampleBlur(func(x, y float64, add func(x, y float64)) {
+		dx, dy := x-cx, y-cy
+		// about one sample per pixel of arc
+		n := max(1, min(256, int(math.Ceil(math.Hypot(dx, dy)*math.Abs(a)))+1))
+		for k := range n {
+			t := a * (float64(k)/float64(max(n-1, 1)) - 0.5)
+			sin, cos := math.Sincos(t)
+			add(cx+dx*cos-dy*sin, cy+dx*sin+dy*cos)
+		}
+	})
+}
+
+// ZoomBlur returns a filter that blurs the image along rays from (cx, cy),
+// as if zooming in on it. amount is the length of the blur relative to the
+// distance from the center.
+//
+// ZoomBlur 返回沿从 (cx, cy) 出发的射线模糊图像的滤镜，如同向该点推近镜头。
+// amount 为模糊长度相对于到中心距离的比例。
+func ZoomBlur(cx, cy, amount float64) Filter {
+	return sampleBlur(func(x, y float64, add func(x, y float64)) {
+		dx, dy := x-cx, y-cy
+		n := max(1, min(256, int(math.Ceil(math.Hypot(dx, dy)*math.Abs(amount)))+1))
+		for k := range n {
+			s := 1 - amount*float64(k)/float64(max(n-1, 1))
+			add(cx+dx*s, cy+dy*s)
+		}
+	})
+}
+
+// sampleBlur returns a filter that averages the premultiplied image at the
+// points that samples adds for every pixel center, in the coordinates of
+// the filtered image.
+func sampleBlur(samples func(x, y float64, add func(x, y float64))) Filter {
+	return FilterFunc(func(src *image.NRGBA) *image.NRGBA {
+		p := premultipliedPix(src)
+		w, h := src.Rect.Dx(), src.Rect.Dy()
+		out := make([]float64, len(p))
+		pixop.Rows(Workers(), h, func(y0, y1 int) {
+			for y := y0; y < y1; y++ {
+				for x := range w {
+					var sum [4]float64
+					n := 0
+					samples(float64(x)+0.5, float64(y)+0.5, func(x, y float64) {
+						c := bilinearPix(p, w, h, x-0.5, y-0.5)
+						for i := range sum {
+							sum[i] += c[i]
+						}
+						n++
+					})
+					i := 4 * (y*w + x)
+					for c := range sum {
+						out[i+c] = sum[c] / float64(max(n, 1))
+					}
+				}
+			}
+		})
+		return fromPremultipliedPix(out, src.Rect)
+	})
+}
+
+// bilinearPix samples the premultiplied pixels p of a w x h image at
+// (x, y) in pixel units, repeating the edge pixels.
+func bilinearPix(p []float64, w, h int, x, y float64) (c [4]float64) {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := x-x0, y-y0
+	at := func(x, y int) []float64 {
+		i := 4 * (max(0, min(h-1, y))*w + max(0, min(w-1, x)))
+		return p[i : i+4]
+	}
+	ix, iy := int(x0), int(y0)
+	c00, c10, c01, c11 := at(ix, iy), at(ix+1, iy), at(ix, iy+1), at(ix+1, iy+1)
+	for i := range c {
+		c[i] = mix(mix(c00[i], c10[i], fx), mix(c01[i], c11[i], fx), fy)
+	}
+	return
+}
@@ -0,0 +1,96 @@
+package gg
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/disintegration/imaging"
+)
+
+func TestGaussBoxes(t *testing.T) {
+	for _, sigma := range []float64{0.8, 2, 5, 12.5, 40} {
+		var variance float64
+		for _, r := range gaussBoxes(sigma) {
+			variance += float64((2*r+1)*(2*r+1)-1) / 12
+		}
+		if d := variance/(sigma*sigma) - 1; d < -0.2 || d > 0.2 {
+			t.Errorf("sigma %v: expected a variance of %v, got %v", sigma, sigma*sigma, variance)
+		}
+	}
+}
+
+func TestBlur(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(10, 10, 50, 50))
+	for y := 25; y < 35; y++ {
+		for x := 25; x < 35; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{255, 255, 255, 255})
+		}
+	}
+	out := Blur(3).Filter(src)
+	if out.Rect != src.Rect {
+		t.Fatalf("expected bounds %v, got %v", src.Rect, out.Rect)
+	}
+	for y := 10; y < 50; y++ {
+		for x := 10; x < 50; x++ {
+			// no dark fringe on transparency
+			if c := out.NRGBAAt(x, y); c.A > 0 && c.R != 255 {
+				t.Fatalf("(%d, %d): expected white, got %v", x, y, c)
+			}
+		}
+	}
+	if a := out.NRGBAAt(24, 30).A; a < 64 || a > 192 {
+		t.Errorf("expected the edge to be half transparent, got %d", a)
+	}
+	// blurring along x only keeps the rows
+	out = BlurXY(3, 0).Filter(src)
+	if out.NRGBAAt(30, 24).A != 0 || out.NRGBAAt(22, 30).A == 0 {
+		t.Error("expected a horizontal blur")
+	}
+	out = MotionBlur(10, 90).Filter(src)
+	if out.NRGBAAt(30, 22).A == 0 || out.NRGBAAt(22, 30).A != 0 {
+		t.Error("expected a vertical motion blur")
+	}
+}
+
+func TestBlurUniform(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 30, 20))
+	c := color.NRGBA{40, 120, 200, 255}
+	for y := range 20 {
+		for x := range 30 {
+			src.SetNRGBA(x, y, c)
+		}
+	}
+	for _, f := range []Filter{Blur(4), BoxBlur(5, 2), MotionBlur(8, 30), RadialBlur(10, 10, 30), ZoomBlur(10, 10, 0.5)} {
+		out := f.Filter(src)
+		for y := range 20 {
+			for x := range 30 {
+				if got := out.NRGBAAt(x, y); !sameColor(got, c) {
+					t.Fatalf("(%d, %d): expected %v, got %v", x, y, c, got)
+				}
+			}
+		}
+	}
+}
+
+func TestBlurParallel(t *testing.T) {
+	src := ImageToNRGBA(gridImage())
+	want := blurXY(src, 2.5, 2.5, 1)
+	if got := blurXY(src, 2.5, 2.5, 4); string(got.Pix) != string(want.Pix) {
+		t.Fatal("expected the same blur on more CPUs")
+	}
+}
+
+func BenchmarkBlur(b *testing.B) {
+	src := image.NewNRGBA(image.Rect(0, 0, 1000, 1000))
+	b.Run("gg", func(b *testing.B) {
+		for range b.N {
+			Blur(10).Filter(src)
+		}
+	})
+	b.Run("imaging", func(b *testing.B) {
+		for range b.N {
+			imaging.Blur(src, 10)
+		}
+	})
+}
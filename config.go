@@ -1,4 +1,34 @@
 package gg
 
+import (
+	"math"
+	"runtime"
+	"sync/atomic"
+)
+
 // ReturnErrOnGPUFailed but not silently fallback to CPU.
 var ReturnErrOnGPUFailed = false
+
+// workers holds the value set by SetWorkers, 0 meaning every CPU.
+var workers atomic.Int32
+
+// SetWorkers sets the number of goroutines that filters, blurs, Resize,
+// the factory package and contexts drawing with SetParallel split their
+// work across. 1 runs everything on the calling goroutine, and 0 or less
+// restores the default of one goroutine per CPU.
+//
+// SetWorkers 设置滤镜、模糊、Resize、factory 包以及启用 SetParallel 的 Context 分配工作所用的协程数。
+// 为 1 时全部在调用协程上运行，不大于 0 时恢复为默认的每个 CPU 一个协程。
+func SetWorkers(n int) {
+	workers.Store(int32(min(max(n, 0), math.MaxInt32)))
+}
+
+// Workers returns the number of goroutines set by SetWorkers.
+//
+// Workers 返回 SetWorkers 设置的协程数。
+func Workers() int {
+	if n := workers.Load(); n > 0 {
+		return int(n)
+	}
+	return runtime.NumCPU()
+}
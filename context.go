@@ -22,6 +22,7 @@ import (
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/basicfont"
 	"golang.org/x/image/math/f64"
+	"golang.org/x/image/math/fixed"
 )
 
 // LineCap defines the shape at the end of a stroked path.
@@ -91,7 +92,8 @@ type Context struct {
 	width         int
 	height        int
 	rasterizer    *raster.Rasterizer
-	im            *image.RGBA
+	im            draw.Image
+	origin        image.Point
 	mask          *image.Alpha
 	color         color.Color
 	fillPattern   Pattern
@@ -107,6 +109,11 @@ type Context struct {
 	lineCap       LineCap
 	lineJoin      LineJoin
 	fillRule      FillRule
+	pixelSnap     bool
+	hairline      bool
+	antialias     Antialias
+	workers       int
+	bands         []*raster.Rasterizer
 	fontFace      font.Face
 	fontHeight    float64
 	matrix        Matrix
@@ -133,10 +140,31 @@ func NewContextForImage(im image.Image) *Context {
 }
 
 // NewContextForRGBA prepares a context for rendering onto the specified image.
-// No copy is made.
+// No copy is made. The image may be a SubImage of a larger one, in which case
+// the point (0, 0) of the context is the top-left corner of the image.
 //
 // 准备渲染到指定图像的上下文。没有复制。
+// 图像可以是更大图像的 SubImage，此时上下文的点 (0, 0) 为该图像的左上角。
 func NewContextForRGBA(im *image.RGBA) *Context {
+	return newContext(im)
+}
+
+// NewContextForDrawImage prepares a context for rendering onto any
+// draw.Image, such as an *image.NRGBA, *image.Gray or *image.Paletted, by
+// reading and writing its colors. No copy is made. Like with
+// NewContextForRGBA, the image may be a SubImage.
+//
+// NewContextForDrawImage 准备渲染到任意 draw.Image（如 *image.NRGBA、*image.Gray 或 *image.Paletted）的上下文，
+// 通过读写其颜色进行绘制。没有复制。与 NewContextForRGBA 一样，图像可以是 SubImage。
+func NewContextForDrawImage(im draw.Image) *Context {
+	return newContext(im)
+}
+
+// newContext prepares a context for rendering onto im, moving its origin
+// to (0, 0).
+func newContext(im draw.Image) *Context {
+	origin := im.Bounds().Min
+	im = translate(im, origin.Mul(-1))
 	w := im.Bounds().Size().X
 	h := im.Bounds().Size().Y
 	return &Context{
@@ -144,6 +172,7 @@ func NewContextForRGBA(im *image.RGBA) *Context {
 		height:        h,
 		rasterizer:    raster.NewRasterizer(w, h),
 		im:            im,
+		origin:        origin,
 		color:         color.Transparent,
 		fillPattern:   defaultFillStyle,
 		strokePattern: defaultStrokeStyle,
@@ -210,6 +239,34 @@ const (
 	// 参见 Mitchell 和 Netravali，“计算机图形学中的重构过滤器”，
 	// 计算机图形学，卷.22，第 4 期，第 221-228 页。
 	CatmullRom
+
+	// Mitchell is the cubic BC-spline kernel with B=1/3 and C=1/3, which
+	// Mitchell and Netravali recommend. It is softer than CatmullRom, with
+	// less ringing.
+	//
+	// Mitchell 是参数 B=1/3 和 C=1/3 的三次 BC 样条核，为 Mitchell 和 Netravali 所推荐。
+	// 它比 CatmullRom 柔和，振铃更少。
+	Mitchell
+
+	// Lanczos2 is the Lanczos kernel with two lobes. It is sharp and slightly
+	// faster than Lanczos3.
+	//
+	// Lanczos2 是两瓣的 Lanczos 核。它清晰，且比 Lanczos3 稍快。
+	Lanczos2
+
+	// Lanczos3 is the Lanczos kernel with three lobes. It is slow, but gives
+	// the sharpest results, especially when scaling down photos.
+	//
+	// Lanczos3 是三瓣的 Lanczos 核。它很慢，但结果最清晰，尤其适合缩小照片。
+	Lanczos3
+
+	// Area averages the source pixels covered by every destination pixel
+	// when scaling down, and repeats them when scaling up. It avoids moire
+	// patterns when scaling down by large factors.
+	//
+	// Area 在缩小时对每个目标像素覆盖的源像素取平均，放大时重复源像素。
+	// 大倍数缩小时可避免摩尔纹。
+	Area
 )
 
 // 变换器
@@ -223,6 +280,14 @@ func (s ScaleStyle) transformer() draw.Interpolator {
 		return draw.NearestNeighbor
 	case CatmullRom:
 		return draw.CatmullRom
+	case Mitchell:
+		return mitchellKernel
+	case Lanczos2:
+		return lanczos2Kernel
+	case Lanczos3:
+		return lanczos3Kernel
+	case Area:
+		return areaKernel
 	}
 	return draw.BiLinear // BiLinear by default. 默认情况下为双线性。
 }
@@ -274,11 +339,18 @@ func (dc *Context) GetCurrentPoint() (Point, bool) {
 	return Point{}, false
 }
 
-// Image returns the image that has been drawn by this context.
+// Image returns the image that has been drawn by this context, with the
+// bounds of the image the context was created for. Contexts backed by a
+// *RGBAF32 return a copy converted to a 16-bit sRGB *image.RGBA64.
 //
-// 返回在此上下文中绘制的图像。
+// 返回在此上下文中绘制的图像，其边界与创建上下文时的图像相同。
+// 由 *RGBAF32 支持的上下文返回转换为 16 位 sRGB 的 *image.RGBA64 副本。
 func (dc *Context) Image() image.Image {
-	return dc.im
+	im := dc.im
+	if f, ok := im.(*RGBAF32); ok {
+		im = f.ToRGBA64()
+	}
+	return translate(im, dc.origin)
 }
 
 // Width returns the width of the image in pixels.
@@ -309,21 +381,21 @@ func (dc *Context) H() int {
 //
 // 将图像编码为 PNG 并将其写入磁盘。
 func (dc *Context) SavePNG(path string) error {
-	return fio.SavePNG(path, dc.im)
+	return fio.SavePNG(path, dc.Image())
 }
 
 // SaveJPG encodes the image as a JPG and writes it to disk.
 //
 // 将图像编码为 JPG 并将其写入磁盘。
 func (dc *Context) SaveJPG(path string, quality int) error {
-	return fio.SaveJPG(path, dc.im, quality)
+	return fio.SaveJPG(path, dc.Image(), quality)
 }
 
 // EncodePNG encodes the image as a PNG and writes it to the provided io.Writer.
 //
 // 将图像编码为 PNG 并将其写入提供的 io.Writer
 func (dc *Context) EncodePNG(w io.Writer) error {
-	return png.Encode(w, dc.im)
+	return png.Encode(w, dc.Image())
 }
 
 // EncodeJPG encodes the image as a JPG and writes it to the provided io.Writer
@@ -334,7 +406,7 @@ func (dc *Context) EncodePNG(w io.Writer) error {
 // 在 JPEG 4:2:0 基线格式中，使用给定选项。
 // 如果为nil，则使用默认参数 *jpeg.Options 进行传递
 func (dc *Context) EncodeJPG(w io.Writer, o *jpeg.Options) error {
-	return jpeg.Encode(w, dc.im, o)
+	return jpeg.Encode(w, dc.Image(), o)
 }
 
 // SetDash sets the current dash pattern to use. Call with zero arguments to
@@ -433,6 +505,59 @@ func (dc *Context) SetFillRuleEvenOdd() {
 	dc.fillRule = FillRuleEvenOdd
 }
 
+// SetPixelSnap enables or disables snapping of path points to the device
+// pixel grid after the current matrix is applied. Points of the fill path
+// snap to pixel edges. Points of the stroke path snap to pixel centers when
+// the line width in effect while building the path rounds to an odd number
+// of pixels (or hairline mode is enabled), and to pixel edges otherwise, so
+// that axis-aligned lines and rectangles are rendered without blurry edges.
+// Only on-curve points are snapped, the interior of curves is unaffected.
+//
+// SetPixelSnap 启用或禁用在应用当前矩阵后将路径点对齐到设备像素网格。
+// 填充路径的点对齐到像素边缘；当构建路径时的线宽四舍五入为奇数像素
+// （或启用了细线模式）时，描边路径的点对齐到像素中心，否则对齐到像素边缘，
+// 使水平和垂直的线条与矩形渲染时边缘清晰。只有曲线上的端点会被对齐，曲线内部不受影响。
+func (dc *Context) SetPixelSnap(snap bool) {
+	dc.pixelSnap = snap
+}
+
+// SetHairline enables or disables hairline mode, in which strokes are
+// always exactly one device pixel wide regardless of the line width.
+//
+// SetHairline 启用或禁用细线模式，在该模式下描边总是恰好一个设备像素宽，与线宽无关。
+func (dc *Context) SetHairline(hairline bool) {
+	dc.hairline = hairline
+}
+
+// strokeWidth returns the line width used to stroke paths in device pixels.
+func (dc *Context) strokeWidth() float64 {
+	if dc.hairline {
+		return 1
+	}
+	return dc.lineWidth
+}
+
+// strokeFixed converts a device space point of the stroke path to fixed
+// point, snapping it to the pixel grid if enabled.
+func (dc *Context) strokeFixed(p Point) fixed.Point26_6 {
+	if !dc.pixelSnap {
+		return p.Fixed()
+	}
+	if int(math.Round(dc.strokeWidth()))%2 == 1 {
+		return fixp(math.Floor(p.X)+0.5, math.Floor(p.Y)+0.5)
+	}
+	return fixp(math.Round(p.X), math.Round(p.Y))
+}
+
+// fillFixed converts a device space point of the fill path to fixed point,
+// snapping it to the pixel grid if enabled.
+func (dc *Context) fillFixed(p Point) fixed.Point26_6 {
+	if !dc.pixelSnap {
+		return p.Fixed()
+	}
+	return fixp(math.Round(p.X), math.Round(p.Y))
+}
+
 // Color Setters
 // 颜色设定器
 
@@ -443,22 +568,34 @@ func (dc *Context) setFillAndStrokeColor(c color.Color) {
 	dc.strokePattern = NewSolidPattern(c)
 }
 
-// SetFillStyle sets current fill style
+// SetFillStyle sets current fill style. The pattern is transformed by the
+// current matrix, so it moves along with the shapes drawn after the same
+// transformation.
 //
-// 设置当前填充样式
+// 设置当前填充样式。图案会按当前矩阵变换，因此会随相同变换下绘制的图形一起移动。
 func (dc *Context) SetFillStyle(pattern Pattern) {
 	// if pattern is SolidPattern, also change dc.color(for dc.Clear, dc.drawString)
 	if fillStyle, ok := pattern.(*solidPattern); ok {
 		dc.color = fillStyle.color
 	}
-	dc.fillPattern = pattern
+	dc.fillPattern = dc.transformPattern(pattern)
 }
 
-// SetStrokeStyle sets current stroke style
+// SetStrokeStyle sets current stroke style. The pattern is transformed by
+// the current matrix like in SetFillStyle.
 //
-// 设置当前笔划样式
+// 设置当前笔划样式。与 SetFillStyle 相同，图案会按当前矩阵变换。
 func (dc *Context) SetStrokeStyle(pattern Pattern) {
-	dc.strokePattern = pattern
+	dc.strokePattern = dc.transformPattern(pattern)
+}
+
+// transformPattern applies the current matrix to pattern unless it is
+// the identity or pattern is solid.
+func (dc *Context) transformPattern(pattern Pattern) Pattern {
+	if _, ok := pattern.(*solidPattern); ok || dc.matrix == Identity() {
+		return pattern
+	}
+	return NewTransformedPattern(pattern, dc.matrix)
 }
 
 // SetColor sets the current color(for both fill and stroke).
@@ -530,12 +667,12 @@ func (dc *Context) SetRGB(r, g, b float64) {
 // 在当前路径中从指定点开始新的子路径。
 func (dc *Context) MoveTo(x, y float64) {
 	if dc.hasCurrent {
-		dc.fillPath.Add1(dc.start.Fixed())
+		dc.fillPath.Add1(dc.fillFixed(dc.start))
 	}
 	x, y = dc.TransformPoint(x, y)
 	p := Point{x, y}
-	dc.strokePath.Start(p.Fixed())
-	dc.fillPath.Start(p.Fixed())
+	dc.strokePath.Start(dc.strokeFixed(p))
+	dc.fillPath.Start(dc.fillFixed(p))
 	dc.start = p
 	dc.current = p
 	dc.hasCurrent = true
@@ -552,8 +689,8 @@ func (dc *Context) LineTo(x, y float64) {
 	} else {
 		x, y = dc.TransformPoint(x, y)
 		p := Point{x, y}
-		dc.strokePath.Add1(p.Fixed())
-		dc.fillPath.Add1(p.Fixed())
+		dc.strokePath.Add1(dc.strokeFixed(p))
+		dc.fillPath.Add1(dc.fillFixed(p))
 		dc.current = p
 	}
 }
@@ -572,8 +709,8 @@ func (dc *Context) QuadraticTo(x1, y1, x2, y2 float64) {
 	x2, y2 = dc.TransformPoint(x2, y2)
 	p1 := Point{x1, y1}
 	p2 := Point{x2, y2}
-	dc.strokePath.Add2(p1.Fixed(), p2.Fixed())
-	dc.fillPath.Add2(p1.Fixed(), p2.Fixed())
+	dc.strokePath.Add2(p1.Fixed(), dc.strokeFixed(p2))
+	dc.fillPath.Add2(p1.Fixed(), dc.fillFixed(p2))
 	dc.current = p2
 }
 
@@ -599,15 +736,21 @@ func (dc *Context) CubicTo(x1, y1, x2, y2, x3, y3 float64) error {
 		return err
 	}
 	previous := dc.current.Fixed()
-	for _, p := range points[1:] {
+	last := len(points) - 2
+	for i, p := range points[1:] {
 		f := p.Fixed()
 		if f == previous {
 			// TODO: this fixes some rendering issues but not all
 			continue
 		}
 		previous = f
-		dc.strokePath.Add1(f)
-		dc.fillPath.Add1(f)
+		if i == last && dc.pixelSnap {
+			dc.strokePath.Add1(dc.strokeFixed(p))
+			dc.fillPath.Add1(dc.fillFixed(p))
+		} else {
+			dc.strokePath.Add1(f)
+			dc.fillPath.Add1(f)
+		}
 		dc.current = p
 	}
 	return nil
@@ -620,8 +763,8 @@ func (dc *Context) CubicTo(x1, y1, x2, y2, x3, y3 float64) error {
 // 如果没有当前点，这是一个空操作。
 func (dc *Context) ClosePath() {
 	if dc.hasCurrent {
-		dc.strokePath.Add1(dc.start.Fixed())
-		dc.fillPath.Add1(dc.start.Fixed())
+		dc.strokePath.Add1(dc.strokeFixed(dc.start))
+		dc.fillPath.Add1(dc.fillFixed(dc.start))
 		dc.current = dc.start
 	}
 }
@@ -642,7 +785,7 @@ func (dc *Context) ClearPath() {
 // 在当前路径中开始一个新的子路径。 此操作后没有当前点。
 func (dc *Context) NewSubPath() {
 	if dc.hasCurrent {
-		dc.fillPath.Add1(dc.start.Fixed())
+		dc.fillPath.Add1(dc.fillFixed(dc.start))
 	}
 	dc.hasCurrent = false
 }
@@ -673,29 +816,21 @@ func (dc *Context) joiner() raster.Joiner {
 }
 
 func (dc *Context) stroke(painter raster.Painter) error {
-	path := dc.strokePath
+	fp, err := flattenPath(dc.strokePath)
+	if err != nil {
+		return err
+	}
 	if len(dc.dashes) > 0 {
-		var err error
-		path, err = dashed(path, dc.dashes, dc.dashOffset)
-		if err != nil {
-			return err
-		}
-	} else {
-		// TODO: this is a temporary workaround to remove tiny segments
-		// that result in rendering issues
-		// TODO:这是一个临时解决方案，用于删除微小的片段
-		// 这会导致渲染问题
-		fp, err := flattenPath(path)
-		if err != nil {
-			return err
-		}
-		path = rasterPath(fp)
+		fp = dashPath(fp, dc.dashes, dc.dashOffset)
 	}
-	r := dc.rasterizer
-	r.UseNonZeroWinding = true
-	r.Clear()
-	r.AddStroke(path, fix(dc.lineWidth), dc.capper(), dc.joiner())
-	r.Rasterize(painter)
+	// TODO: rasterPath is a temporary workaround to remove tiny segments
+	// that result in rendering issues
+	// TODO:rasterPath 是一个临时解决方案，用于删除微小的片段
+	// 这会导致渲染问题
+	path := rasterPath(fp)
+	outline := make(raster.Path, 0, len(path)*4)
+	raster.Stroke(&outline, path, fix(dc.strokeWidth()), dc.capper(), dc.joiner())
+	dc.rasterize(outline, true, painter)
 	return nil
 }
 
@@ -705,13 +840,9 @@ func (dc *Context) fill(painter raster.Painter) {
 	if dc.hasCurrent {
 		path = make(raster.Path, len(dc.fillPath))
 		copy(path, dc.fillPath)
-		path.Add1(dc.start.Fixed())
+		path.Add1(dc.fillFixed(dc.start))
 	}
-	r := dc.rasterizer
-	r.UseNonZeroWinding = dc.fillRule == FillRuleWinding
-	r.Clear()
-	r.AddPath(path)
-	r.Rasterize(painter)
+	dc.rasterize(path, dc.fillRule == FillRuleWinding, painter)
 }
 
 // StrokePreserve strokes the current path with the current color, line width,
@@ -722,11 +853,11 @@ func (dc *Context) fill(painter raster.Painter) {
 // 此操作后将保留路径。
 func (dc *Context) StrokePreserve() error {
 	var painter raster.Painter
-	if dc.mask == nil {
+	if im, ok := dc.im.(*image.RGBA); ok && dc.mask == nil {
 		if pattern, ok := dc.strokePattern.(*solidPattern); ok {
 			// with a nil mask and a solid color pattern, we can be more efficient
 			// TODO: refactor so we don't have to do this type assertion stuff?
-			p := raster.NewRGBAPainter(dc.im)
+			p := raster.NewRGBAPainter(im)
 			p.SetColor(pattern.color)
 			painter = p
 		}
@@ -754,13 +885,13 @@ func (dc *Context) Stroke() error {
 // FillPreserve 用当前颜色填充当前路径。 打开的子路径是隐式关闭的。 此操作后将保留路径。
 func (dc *Context) FillPreserve() {
 	var painter raster.Painter
-	if dc.mask == nil {
+	if im, ok := dc.im.(*image.RGBA); ok && dc.mask == nil {
 		if pattern, ok := dc.fillPattern.(*solidPattern); ok {
 			// with a nil mask and a solid color pattern, we can be more efficient
 			// TODO: refactor so we don't have to do this type assertion stuff?
 			// 使用 nil 掩码和纯色图案，我们可以更高效
 			// TODO: 重构所以我们不必做这种类型断言的东西？
-			p := raster.NewRGBAPainter(dc.im)
+			p := raster.NewRGBAPainter(im)
 			p.SetColor(pattern.color)
 			painter = p
 		}
@@ -941,29 +1072,7 @@ func (dc *Context) DrawRoundedRectangle(x, y, w, h, r float64) {
 //
 // DrawEllipticalArc 绘制以 (x, y) 为中心的椭圆弧。
 func (dc *Context) DrawEllipticalArc(x, y, rx, ry, angle1, angle2 float64) {
-	const n = 16
-	for i := range n {
-		p1 := float64(i+0) / n
-		p2 := float64(i+1) / n
-		a1 := angle1 + (angle2-angle1)*p1
-		a2 := angle1 + (angle2-angle1)*p2
-		x0 := x + rx*math.Cos(a1)
-		y0 := y + ry*math.Sin(a1)
-		x1 := x + rx*math.Cos((a1+a2)/2)
-		y1 := y + ry*math.Sin((a1+a2)/2)
-		x2 := x + rx*math.Cos(a2)
-		y2 := y + ry*math.Sin(a2)
-		cx := 2*x1 - x0/2 - x2/2
-		cy := 2*y1 - y0/2 - y2/2
-		if i == 0 {
-			if dc.hasCurrent {
-				dc.LineTo(x0, y0)
-			} else {
-				dc.MoveTo(x0, y0)
-			}
-		}
-		dc.QuadraticTo(cx, cy, x2, y2)
-	}
+	dc.ellipticalArc(x, y, rx, ry, 0, angle1, angle2, 16)
 }
 
 // DrawEllipse draws an ellipse centered at (x, y).
@@ -995,17 +1104,7 @@ func (dc *Context) DrawCircle(x, y, r float64) {
 //
 // DrawRegularPolygon 绘制以 (x, y) 为中心的 n 边正多边形。
 func (dc *Context) DrawRegularPolygon(n int, x, y, r, rotation float64) {
-	angle := 2 * math.Pi / float64(n)
-	rotation -= math.Pi / 2
-	if n%2 == 0 {
-		rotation += angle / 2
-	}
-	dc.NewSubPath()
-	for i := range n {
-		a := rotation + angle*float64(i)
-		dc.LineTo(x+r*math.Cos(a), y+r*math.Sin(a))
-	}
-	dc.ClosePath()
+	dc.DrawPolygon(regularPolygonPoints(n, x, y, r, rotation))
 }
 
 // LoadImage loads an image from the given path and draws it at (x, y).
@@ -1038,20 +1137,115 @@ func (dc *Context) DrawImageAnchored(im image.Image, x, y int, ax, ay float64) {
 	s := im.Bounds().Size()
 	x -= int(ax * float64(s.X))
 	y -= int(ay * float64(s.Y))
-	transformer := dc.scaleStyle.transformer()
 	fx, fy := float64(x), float64(y)
-	m := dc.matrix.Translate(fx, fy)
+	dc.drawImage(im, im.Bounds(), dc.matrix.Translate(fx, fy), dc.mask)
+}
+
+// drawImage composites the sr part of im onto the context, mapping source
+// coordinates to device space with m and clipping with mask.
+func (dc *Context) drawImage(im image.Image, sr image.Rectangle, m Matrix, mask *image.Alpha) {
+	transformer := dc.scaleStyle.transformer()
 	s2d := f64.Aff3{m.XX, m.XY, m.X0, m.YX, m.YY, m.Y0}
-	if dc.mask == nil {
-		transformer.Transform(dc.im, s2d, im, im.Bounds(), draw.Over, nil)
+	if mask == nil {
+		transformer.Transform(dc.im, s2d, im, sr, draw.Over, nil)
 	} else {
-		transformer.Transform(dc.im, s2d, im, im.Bounds(), draw.Over, &draw.Options{
-			DstMask:  dc.mask,
+		transformer.Transform(dc.im, s2d, im, sr, draw.Over, &draw.Options{
+			DstMask:  mask,
 			DstMaskP: image.Point{},
 		})
 	}
 }
 
+// DrawImageRect draws the sr part of the specified image scaled into the
+// rectangle at (x, y) with size (w, h). The image is interpolated with the
+// current scale style and is subject to the current matrix and clip.
+//
+// DrawImageRect 将指定图像的 sr 部分缩放绘制到位于 (x, y)、大小为 (w, h) 的矩形中。
+// 图像使用当前缩放样式插值，并受当前矩阵和裁剪区域影响。
+func (dc *Context) DrawImageRect(im image.Image, sr image.Rectangle, x, y, w, h float64) {
+	sr = sr.Intersect(im.Bounds())
+	if sr.Empty() || w == 0 || h == 0 {
+		return
+	}
+	sx, sy := w/float64(sr.Dx()), h/float64(sr.Dy())
+	m := dc.matrix.Translate(x, y).Scale(sx, sy).Translate(-float64(sr.Min.X), -float64(sr.Min.Y))
+	dc.drawImage(im, sr, m, dc.mask)
+}
+
+// ImageFit defines how DrawImageFit sizes an image within its box.
+//
+// ImageFit 定义 DrawImageFit 在框内调整图像大小的方式。
+type ImageFit int
+
+// Image fit modes, named after the CSS object-fit values.
+//
+// 图像适应模式，以 CSS object-fit 的取值命名。
+const (
+	FitFill      ImageFit = iota // Stretch to fill the box. 拉伸以填满框。
+	FitContain                   // Scale to fit inside the box, keeping the aspect ratio. 保持宽高比缩放以完整放入框内。
+	FitCover                     // Scale to cover the box, keeping the aspect ratio, and crop. 保持宽高比缩放以覆盖整个框并裁切。
+	FitNone                      // Keep the natural size and crop. 保持原始大小并裁切。
+	FitScaleDown                 // Like FitNone or FitContain, whichever is smaller. 取 FitNone 与 FitContain 中较小者。
+)
+
+// DrawImageFit draws the specified image centered in the box at (x, y)
+// with size (w, h), sized according to fit. Parts outside of the box are
+// cropped. The image is interpolated with the current scale style and is
+// subject to the current matrix and clip.
+//
+// DrawImageFit 将指定图像居中绘制到位于 (x, y)、大小为 (w, h) 的框中，
+// 并按照 fit 调整大小。超出框的部分会被裁切。
+// 图像使用当前缩放样式插值，并受当前矩阵和裁剪区域影响。
+func (dc *Context) DrawImageFit(im image.Image, x, y, w, h float64, fit ImageFit) {
+	b := im.Bounds()
+	iw, ih := float64(b.Dx()), float64(b.Dy())
+	if iw == 0 || ih == 0 || w <= 0 || h <= 0 {
+		return
+	}
+	var s float64
+	switch fit {
+	case FitContain:
+		s = min(w/iw, h/ih)
+	case FitCover:
+		s = max(w/iw, h/ih)
+	case FitNone:
+		s = 1
+	case FitScaleDown:
+		s = min(w/iw, h/ih, 1)
+	default:
+		dc.DrawImageRect(im, b, x, y, w, h)
+		return
+	}
+	dw, dh := iw*s, ih*s
+	m := dc.matrix.Translate(x+(w-dw)/2, y+(h-dh)/2).Scale(s, s).Translate(-float64(b.Min.X), -float64(b.Min.Y))
+	mask := dc.mask
+	if dw > w || dh > h {
+		mask = dc.rectMask(x, y, w, h)
+	}
+	dc.drawImage(im, b, m, mask)
+}
+
+// rectMask returns the current clip intersected with the rectangle at
+// (x, y) with size (w, h) transformed by the current matrix, leaving the
+// current path untouched.
+func (dc *Context) rectMask(x, y, w, h float64) *image.Alpha {
+	var path raster.Path
+	for i, p := range [...]Point{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}, {x, y}} {
+		px, py := dc.TransformPoint(p.X, p.Y)
+		if i == 0 {
+			path.Start(fixp(px, py))
+		} else {
+			path.Add1(fixp(px, py))
+		}
+	}
+	mask := image.NewAlpha(image.Rect(0, 0, dc.width, dc.height))
+	dc.rasterize(path, true, raster.NewAlphaOverPainter(mask))
+	if dc.mask != nil {
+		draw.DrawMask(mask, mask.Bounds(), mask, image.Point{}, dc.mask, image.Point{}, draw.Src)
+	}
+	return mask
+}
+
 // Text Functions
 // 文本函数
 
@@ -1095,7 +1289,7 @@ func (dc *Context) FontHeight() float64 {
 }
 
 // 绘制文本 x 为宽度的起点 y 为高度的终点
-func (dc *Context) drawString(im *image.RGBA, s string, x, y float64) {
+func (dc *Context) drawString(im draw.Image, s string, x, y float64) {
 	d := &font.Drawer{
 		Dst:  im,
 		Src:  image.NewUniform(dc.color),
@@ -1116,7 +1310,11 @@ func (dc *Context) drawString(im *image.RGBA, s string, x, y float64) {
 			continue
 		}
 		sr := dr.Sub(dr.Min)
-		transformer := draw.BiLinear
+		var transformer draw.Transformer = draw.BiLinear
+		if dc.antialias == AntialiasNone {
+			transformer = draw.NearestNeighbor
+			mask = monochromeMask{mask}
+		}
 		fx, fy := float64(dr.Min.X), float64(dr.Min.Y)
 		m := dc.matrix.Translate(fx, fy)
 		s2d := f64.Aff3{m.XX, m.XY, m.X0, m.YX, m.YY, m.Y0}
@@ -1356,7 +1554,7 @@ func (dc *Context) Pop() {
 
 // Hash 计算已绘制图片的md5
 func (dc *Context) Hash() [md5.Size]byte {
-	return md5.Sum(dc.im.Pix)
+	return md5.Sum(dc.pix())
 }
 
 // String 以哈希形式打印已绘制图片的 md5, 形如 <gg.Context [md5]>
@@ -1364,7 +1562,7 @@ func (dc *Context) String() string {
 	sb := strings.Builder{}
 	h := md5.New()
 	var buf [md5.Size]byte
-	_, err := h.Write(dc.im.Pix)
+	_, err := h.Write(dc.pix())
 	sb.WriteString("<gg.Context ")
 	if err == nil {
 		_, _ = hex.NewEncoder(&sb).Write(h.Sum(buf[:0]))
@@ -1376,6 +1574,23 @@ func (dc *Context) String() string {
 	return sb.String()
 }
 
+// pix returns the bytes of the drawn image, 16-bit for the contexts that
+// are not backed by an *image.RGBA.
+func (dc *Context) pix() []byte {
+	switch im := dc.im.(type) {
+	case *image.RGBA:
+		if im.Stride == 4*dc.width {
+			return im.Pix[:im.Stride*dc.height]
+		}
+		return ImageToRGBA(im).Pix
+	case *image.RGBA64:
+		if im.Stride == 8*dc.width {
+			return im.Pix[:im.Stride*dc.height]
+		}
+	}
+	return ImageToRGBA64(dc.im).Pix
+}
+
 // TakeThemeColorsKMeans extracts the k dominant colors from the drawn image using k-means.
 //
 // TakeThemeColorsKMeans 使用 k-means 算法从已绘制图像中提取 k 个主色。
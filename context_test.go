@@ -381,3 +381,65 @@ func BenchmarkCircles(b *testing.B) {
 		dc.Fill()
 	}
 }
+
+// centeredGrid draws the grid of TestGrid with every end point on a pixel center.
+func centeredGrid() *Context {
+	dc := NewContext(100, 100)
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+	for i := 10; i < 100; i += 10 {
+		x := float64(i) + 0.5
+		dc.DrawLine(x, 0.5, x, 100.5)
+		dc.DrawLine(0.5, x, 100.5, x)
+	}
+	dc.SetRGB(0, 0, 0)
+	dc.Stroke()
+	return dc
+}
+
+func TestPixelSnap(t *testing.T) {
+	grid := func(snap bool) *Context {
+		dc := NewContext(100, 100)
+		dc.SetRGB(1, 1, 1)
+		dc.Clear()
+		dc.SetPixelSnap(snap)
+		dc.Scale(0.97, 1.03)
+		for i := 10; i < 100; i += 10 {
+			x := (float64(i) + 0.2) / 0.97
+			y := (float64(i) + 0.2) / 1.03
+			dc.DrawLine(x, 0, x, 100/1.03)
+			dc.DrawLine(0, y, 100/0.97, y)
+		}
+		dc.SetRGB(0, 0, 0)
+		dc.Stroke()
+		return dc
+	}
+	snapped := grid(true)
+	if err := saveImage(snapped, "TestPixelSnap"); err != nil {
+		t.Fatal(err)
+	}
+	checkHash(t, snapped, centeredGrid().String())
+	if grid(false).String() == snapped.String() {
+		t.Fatal("unsnapped grid should be blurry")
+	}
+}
+
+func TestHairline(t *testing.T) {
+	dc := NewContext(100, 100)
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+	dc.SetPixelSnap(true)
+	dc.SetHairline(true)
+	dc.SetLineWidth(7)
+	dc.Scale(10, 10)
+	for i := 1; i < 10; i++ {
+		dc.DrawLine(float64(i), 0, float64(i), 10)
+		dc.DrawLine(0, float64(i), 10, float64(i))
+	}
+	dc.SetRGB(0, 0, 0)
+	dc.Stroke()
+	if err := saveImage(dc, "TestHairline"); err != nil {
+		t.Fatal(err)
+	}
+	checkHash(t, dc, centeredGrid().String())
+}
@@ -0,0 +1,165 @@
+package gg
+
+import (
+	"image"
+	"math"
+)
+
+// cropAnalysisSize is the longest side of the copy of the image that
+// SmartCrop analyzes.
+const cropAnalysisSize = 96
+
+// SmartCrop returns the rectangle of im with the aspect ratio w:h that
+// best frames its content. It is the largest such rectangle, placed where
+// it covers the most interesting parts of the image: edges, colors that
+// stand out from the rest of the image and skin tones, weighted towards
+// the center of the crop so subjects and faces are not cut off.
+//
+// SmartCrop 返回 im 中宽高比为 w:h、最能框住其内容的矩形。
+// 它是此类矩形中最大的一个，放置在覆盖图像最有趣部分的位置：边缘、与图像其余部分不同的颜色以及肤色，
+// 并向裁剪区域中心加权，因此主体与人脸不会被切掉。
+func SmartCrop(im image.Image, w, h int) image.Rectangle {
+	b := im.Bounds()
+	if b.Empty() || w <= 0 || h <= 0 {
+		return b
+	}
+	full := coverSize(b.Size(), w, h)
+	if full == b.Size() {
+		return b
+	}
+	// analyze a small copy, scanning along the axis the crop can move on
+	scale := min(1, cropAnalysisSize/float64(max(b.Dx(), b.Dy())))
+	small := Resize(im, max(1, int(math.Round(float64(b.Dx())*scale))), max(1, int(math.Round(float64(b.Dy())*scale))), Area)
+	e := cropEnergy(small)
+	sw, sh := small.Rect.Dx(), small.Rect.Dy()
+	win := coverSize(small.Rect.Size(), w, h)
+	horizontal := full.X < b.Dx()
+	steps := sh - win.Y
+	if horizontal {
+		steps = sw - win.X
+	}
+	best, bestScore := 0, math.Inf(-1)
+	for k := range max(steps, 0) + 1 {
+		x0, y0 := 0, k
+		if horizontal {
+			x0, y0 = k, 0
+		}
+		var score float64
+		for y := range win.Y {
+			ny := 2*(float64(y)+0.5)/float64(win.Y) - 1
+			for x := range win.X {
+				nx := 2*(float64(x)+0.5)/float64(win.X) - 1
+				score += e[(y0+y)*sw+x0+x] * (1 - 0.3*(nx*nx+ny*ny))
+			}
+		}
+		if score > bestScore {
+			best, bestScore = k, score
+		}
+	}
+	// map the offset back to the image
+	off := image.Point{}
+	if horizontal {
+		off.X = min(b.Dx()-full.X, int(math.Round(float64(best)*float64(b.Dx())/float64(sw))))
+	} else {
+		off.Y = min(b.Dy()-full.Y, int(math.Round(float64(best)*float64(b.Dy())/float64(sh))))
+	}
+	p := b.Min.Add(off)
+	return image.Rectangle{p, p.Add(full)}
+}
+
+// FocalRect returns the largest rectangle of b with the aspect ratio w:h
+// whose center is as close as possible to the focal point (fx, fy), given
+// in [0, 1] relative to b. Use it to cover-fit an image without cutting
+// off a known subject.
+//
+// FocalRect 返回 b 中宽高比为 w:h 的最大矩形，其中心尽量靠近焦点 (fx, fy)，
+// 焦点以相对于 b 的 [0, 1] 坐标给出。可用于在不切掉已知主体的情况下覆盖适应图像。
+func FocalRect(b image.Rectangle, w, h int, fx, fy float64) image.Rectangle {
+	if b.Empty() || w <= 0 || h <= 0 {
+		return b
+	}
+	size := coverSize(b.Size(), w, h)
+	place := func(n, m int, f float64) int {
+		return max(0, min(n-m, int(math.Round(f*float64(n)-float64(m)/2))))
+	}
+	p := b.Min.Add(image.Pt(place(b.Dx(), size.X, fx), place(b.Dy(), size.Y, fy)))
+	return image.Rectangle{p, p.Add(size)}
+}
+
+// coverSize returns the largest size with the aspect ratio w:h that fits
+// in s.
+func coverSize(s image.Point, w, h int) image.Point {
+	if s.X*h > s.Y*w {
+		return image.Pt(max(1, min(s.X, int(math.Round(float64(s.Y*w)/float64(h))))), s.Y)
+	}
+	return image.Pt(s.X, max(1, min(s.Y, int(math.Round(float64(s.X*h)/float64(w))))))
+}
+
+// cropEnergy returns how interesting every pixel of im is, combining its
+// edge strength, its color distance from the mean color and how much it
+// looks like skin, scaled by its opacity.
+func cropEnergy(im *image.NRGBA) []float64 {
+	w, h := im.Rect.Dx(), im.Rect.Dy()
+	rgb := make([][3]float64, w*h)
+	alpha := make([]float64, w*h)
+	luma := make([]float64, w*h)
+	var mean [3]float64
+	var total float64
+	for y := range h {
+		for x := range w {
+			p := im.Pix[y*im.Stride+4*x:]
+			i := y*w + x
+			a := float64(p[3]) / 255
+			for c := range 3 {
+				rgb[i][c] = float64(p[c]) / 255
+				mean[c] += rgb[i][c] * a
+			}
+			alpha[i] = a
+			luma[i] = float64(luma709(p[:4])) / 255
+			total += a
+		}
+	}
+	if total > 0 {
+		for c := range mean {
+			mean[c] /= total
+		}
+	}
+	at := func(x, y int) float64 {
+		return luma[max(0, min(h-1, y))*w+max(0, min(w-1, x))]
+	}
+	e := make([]float64, w*h)
+	for y := range h {
+		for x := range w {
+			i := y*w + x
+			// Sobel gradient of the luma
+			gx := at(x+1, y-1) + 2*at(x+1, y) + at(x+1, y+1) - at(x-1, y-1) - 2*at(x-1, y) - at(x-1, y+1)
+			gy := at(x-1, y+1) + 2*at(x, y+1) + at(x+1, y+1) - at(x-1, y-1) - 2*at(x, y-1) - at(x+1, y-1)
+			edge := min(1, math.Hypot(gx, gy)/4)
+			c := rgb[i]
+			saliency := math.Sqrt((c[0]-mean[0])*(c[0]-mean[0])+(c[1]-mean[1])*(c[1]-mean[1])+(c[2]-mean[2])*(c[2]-mean[2])) / math.Sqrt(3)
+			e[i] = (edge + 0.6*saliency + 1.8*skinTone(c, luma[i])) * alpha[i]
+		}
+	}
+	return e
+}
+
+// skinTone returns how close the color c with the luma l is to a typical
+// skin tone, from 0 to 1, regardless of its brightness.
+func skinTone(c [3]float64, l float64) float64 {
+	if l < 0.2 || l > 0.95 {
+		return 0
+	}
+	mag := math.Sqrt(c[0]*c[0] + c[1]*c[1] + c[2]*c[2])
+	if mag == 0 {
+		return 0
+	}
+	// the normalized skin color of smartcrop.js
+	skin := [3]float64{0.78, 0.57, 0.44}
+	sm := math.Sqrt(skin[0]*skin[0] + skin[1]*skin[1] + skin[2]*skin[2])
+	var d float64
+	for k := range 3 {
+		v := c[k]/mag - skin[k]/sm
+		d += v * v
+	}
+	return max(0, 1-math.Sqrt(d)/0.25)
+}
@@ -0,0 +1,62 @@
+package gg
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// subjectImage returns a flat gray image of size w x h with a textured
+// skin colored subject in r.
+func subjectImage(w, h int, r image.Rectangle) *image.NRGBA {
+	im := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			c := color.NRGBA{90, 100, 110, 255}
+			if (image.Point{x, y}).In(r) {
+				c = color.NRGBA{220, 160, 125, 255}
+				if (x/4+y/4)%2 == 0 {
+					c = color.NRGBA{150, 105, 80, 255}
+				}
+			}
+			im.SetNRGBA(x, y, c)
+		}
+	}
+	return im
+}
+
+func TestSmartCrop(t *testing.T) {
+	subject := image.Rect(220, 30, 280, 70)
+	r := SmartCrop(subjectImage(300, 100, subject), 1, 1)
+	if r.Size() != image.Pt(100, 100) || !subject.In(r) {
+		t.Errorf("expected a 100x100 crop around %v, got %v", subject, r)
+	}
+
+	subject = image.Rect(20, 10, 60, 50)
+	r = SmartCrop(subjectImage(80, 240, subject).SubImage(image.Rect(0, 5, 80, 240)), 16, 9)
+	if r.Size() != image.Pt(80, 45) || r.Min.Y < 5 || !subject.Intersect(r).Eq(subject.Intersect(image.Rect(0, 5, 80, 240))) {
+		t.Errorf("expected an 80x45 crop around %v, got %v", subject, r)
+	}
+
+	b := image.Rect(0, 0, 40, 30)
+	if r := SmartCrop(image.NewNRGBA(b), 4, 3); r != b {
+		t.Errorf("expected the whole image for its own aspect ratio, got %v", r)
+	}
+}
+
+func TestFocalRect(t *testing.T) {
+	b := image.Rect(10, 10, 410, 210)
+	for _, tc := range []struct {
+		fx, fy float64
+		want   image.Rectangle
+	}{
+		{0.5, 0.5, image.Rect(110, 10, 310, 210)},
+		{0.9, 0.1, image.Rect(210, 10, 410, 210)},
+		{0.25, 0.5, image.Rect(10, 10, 210, 210)},
+		{0.4, 0.5, image.Rect(70, 10, 270, 210)},
+	} {
+		if got := FocalRect(b, 1, 1, tc.fx, tc.fy); got != tc.want {
+			t.Errorf("focal point (%v, %v): expected %v, got %v", tc.fx, tc.fy, tc.want, got)
+		}
+	}
+}
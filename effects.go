@@ -8,41 +8,102 @@ import (
 )
 
 // AdjustBrightness 调整亮度 范围：±100%
+//
+// 在原图像上修改，仅作用于当前裁剪蒙版内。使用 ApplyFilterRect 与 Brightness 可仅作用于部分区域。
 func (dc *Context) AdjustBrightness(s float64) {
 	if math.Abs(s) < 0.001 {
 		return
 	}
-	dc.im = (*image.RGBA)(imaging.AdjustBrightness(dc.im, s))
+	dc.ApplyFilter(Brightness(s))
 }
 
 // AdjustContrast 调整对比度 范围：±100%
+//
+// 在原图像上修改，仅作用于当前裁剪蒙版内。使用 ApplyFilterRect 与 Contrast 可仅作用于部分区域。
 func (dc *Context) AdjustContrast(s float64) {
 	if math.Abs(s) < 0.001 {
 		return
 	}
-	dc.im = (*image.RGBA)(imaging.AdjustContrast(dc.im, s))
+	dc.ApplyFilter(Contrast(s))
 }
 
 // AdjustSaturation 调整饱和度 范围：±100%
+//
+// 在原图像上修改，仅作用于当前裁剪蒙版内。使用 ApplyFilterRect 与 Saturation 可仅作用于部分区域。
 func (dc *Context) AdjustSaturation(s float64) {
 	if math.Abs(s) < 0.001 {
 		return
 	}
-	dc.im = (*image.RGBA)(imaging.AdjustSaturation(dc.im, s))
+	dc.ApplyFilter(Saturation(s))
 }
 
 // Sharpen 锐化 范围：±100%
+//
+// 在原图像上修改，仅作用于当前裁剪蒙版内。使用 ApplyFilterRect 与 Sharpen 可仅作用于部分区域。
 func (dc *Context) Sharpen(s float64) {
 	if math.Abs(s) < 0.001 {
 		return
 	}
-	dc.im = (*image.RGBA)(imaging.Sharpen(dc.im, s))
+	dc.ApplyFilter(Sharpen(s))
 }
 
 // Blur 模糊图像 正数
+//
+// 在原图像上修改，仅作用于当前裁剪蒙版内。使用 ApplyFilterRect 与 Blur 可仅作用于部分区域，
+// 例如只模糊卡片背后的背景面板（毛玻璃效果）。
 func (dc *Context) Blur(s float64) {
 	if math.Abs(s) < 0.001 {
 		return
 	}
-	dc.im = (*image.RGBA)(imaging.Blur(dc.im, s))
+	dc.ApplyFilter(Blur(s))
+}
+
+// Brightness returns a filter that changes the brightness of the image by
+// percentage in [-100, 100].
+//
+// Brightness 返回按百分比（[-100, 100]）调整图像亮度的滤镜。
+func Brightness(percentage float64) Filter {
+	return imagingFilter(func(im image.Image) *image.NRGBA {
+		return imaging.AdjustBrightness(im, percentage)
+	})
+}
+
+// Contrast returns a filter that changes the contrast of the image by
+// percentage in [-100, 100].
+//
+// Contrast 返回按百分比（[-100, 100]）调整图像对比度的滤镜。
+func Contrast(percentage float64) Filter {
+	return imagingFilter(func(im image.Image) *image.NRGBA {
+		return imaging.AdjustContrast(im, percentage)
+	})
+}
+
+// Saturation returns a filter that changes the saturation of the image by
+// percentage in [-100, 100].
+//
+// Saturation 返回按百分比（[-100, 100]）调整图像饱和度的滤镜。
+func Saturation(percentage float64) Filter {
+	return imagingFilter(func(im image.Image) *image.NRGBA {
+		return imaging.AdjustSaturation(im, percentage)
+	})
+}
+
+// Sharpen returns a filter that sharpens the image, with sigma the
+// strength of the effect.
+//
+// Sharpen 返回锐化图像的滤镜，sigma 为效果强度。
+func Sharpen(sigma float64) Filter {
+	return imagingFilter(func(im image.Image) *image.NRGBA {
+		return imaging.Sharpen(im, sigma)
+	})
+}
+
+// imagingFilter adapts an imaging function, whose results start at the
+// origin, to a Filter.
+func imagingFilter(f func(im image.Image) *image.NRGBA) Filter {
+	return FilterFunc(func(src *image.NRGBA) *image.NRGBA {
+		out := f(src)
+		out.Rect = out.Rect.Add(src.Rect.Min)
+		return out
+	})
 }
@@ -1,6 +1,8 @@
 package gg
 
 import (
+	"image"
+	"image/color"
 	"testing"
 
 	"github.com/FloatTech/gg/fio"
@@ -16,12 +18,27 @@ func TestBrightness(t *testing.T) {
 	if err := saveImage(dc, "TestBrightness-50"); err != nil {
 		t.Fatal(err)
 	}
-	checkHash(t, dc, "<gg.Context e495391cc1349c0db98431a3f4ef21d8>")
+	// gopher.png is partly transparent. The effects used to store the
+	// straight colors of their result as premultiplied ones, so these hashes
+	// changed when that was fixed.
+	checkHash(t, dc, "<gg.Context 3ef3dd0aa1a4b129fed3ebee9f04ce05>")
+	checkPremultiplied(t, dc.im.(*image.RGBA))
 	dc.AdjustBrightness(70) // 70-50=20
 	if err := saveImage(dc, "TestBrightness+20"); err != nil {
 		t.Fatal(err)
 	}
-	checkHash(t, dc, "<gg.Context 2a1b5df094e1ffb6b8de120d922400bf>")
+	checkHash(t, dc, "<gg.Context 2220211cfdef53cc87ebe258f01067e9>")
+	checkPremultiplied(t, dc.im.(*image.RGBA))
+}
+
+// checkPremultiplied fails if a color component of im exceeds its alpha.
+func checkPremultiplied(t *testing.T, im *image.RGBA) {
+	t.Helper()
+	for i := 0; i < len(im.Pix); i += 4 {
+		if a := im.Pix[i+3]; im.Pix[i] > a || im.Pix[i+1] > a || im.Pix[i+2] > a {
+			t.Fatalf("pixel %d: %v is not premultiplied", i/4, im.Pix[i:i+4])
+		}
+	}
 }
 
 func TestContrast(t *testing.T) {
@@ -34,10 +51,44 @@ func TestContrast(t *testing.T) {
 	if err := saveImage(dc, "TestContrast-50"); err != nil {
 		t.Fatal(err)
 	}
-	checkHash(t, dc, "<gg.Context 71bfdcec14fa18485e85c80b7a7c3fa3>")
+	// see TestBrightness for why the hashes changed
+	checkHash(t, dc, "<gg.Context 0cdc90a228f140bc0d5670ef73e073ea>")
+	checkPremultiplied(t, dc.im.(*image.RGBA))
 	dc.AdjustContrast(100)
 	if err := saveImage(dc, "TestContrast+100"); err != nil {
 		t.Fatal(err)
 	}
-	checkHash(t, dc, "<gg.Context 4854d92c7e15539580349c6876f39070>")
+	checkHash(t, dc, "<gg.Context d02b26f2cbf3663a356decf3e7ddb339>")
+	checkPremultiplied(t, dc.im.(*image.RGBA))
+}
+
+func TestEffectsInPlace(t *testing.T) {
+	im := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	dc := NewContextForRGBA(im)
+	dc.SetRGB255(128, 128, 128)
+	dc.Clear()
+	dc.DrawRectangle(0, 0, 50, 100)
+	dc.Clip()
+	dc.AdjustBrightness(50)
+	dc.ResetClip()
+	if dc.Image() != image.Image(im) {
+		t.Fatal("expected the effect to keep drawing onto the image")
+	}
+	if im.RGBAAt(25, 50) == im.RGBAAt(75, 50) {
+		t.Error("expected the left half to be brighter")
+	}
+	if got := im.RGBAAt(75, 50); got != (color.RGBA{128, 128, 128, 255}) {
+		t.Errorf("expected the right half to be unchanged, got %v", got)
+	}
+	// blur a panel only
+	dc.SetRGB(0, 0, 0)
+	dc.DrawRectangle(70, 40, 10, 10)
+	dc.Fill()
+	dc.ApplyFilterRect(Blur(3), image.Rect(60, 30, 90, 60))
+	if got := im.RGBAAt(65, 45); got == (color.RGBA{128, 128, 128, 255}) {
+		t.Error("expected the panel to be blurred")
+	}
+	if got := im.RGBAAt(95, 45); got != (color.RGBA{128, 128, 128, 255}) {
+		t.Errorf("expected the outside of the panel to be unchanged, got %v", got)
+	}
 }
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/FloatTech/gg"
+)
+
+func TestArcs(*testing.T) {
+	const S = 512
+	dc := gg.NewContext(S, S)
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+
+	// canvas-style tangent arcs rounding the corners of an open polyline
+	dc.MoveTo(40, 460)
+	dc.ArcTo(40, 40, 240, 40, 60)
+	dc.ArcTo(240, 40, 240, 240, 30)
+	dc.LineTo(240, 240)
+	dc.SetRGB(0, 0, 0)
+	dc.SetLineWidth(6)
+	dc.Stroke()
+
+	// svg-style endpoint arcs with every flag combination
+	flags := [][2]bool{{false, false}, {false, true}, {true, false}, {true, true}}
+	colors := []color.Color{gg.Red, gg.Green, gg.Blue, gg.Orange}
+	for i, f := range flags {
+		dc.MoveTo(320, 80+float64(i)*110)
+		dc.SVGArcTo(60, 30, gg.Radians(30), f[0], f[1], 380, 100+float64(i)*110)
+		dc.SetColor(colors[i])
+		dc.Stroke()
+	}
+
+	// rotated ellipses
+	dc.SetRGBA(0, 0, 1, 0.25)
+	for i := range 6 {
+		dc.DrawRotatedEllipse(140, 360, 100, 30, gg.Radians(float64(i*30)))
+		dc.Fill()
+	}
+
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
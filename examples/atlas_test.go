@@ -0,0 +1,30 @@
+package main
+
+import (
+	"image"
+	"image/color/palette"
+	"testing"
+
+	"github.com/FloatTech/gg"
+	"github.com/FloatTech/gg/fio"
+)
+
+func TestAtlas(*testing.T) {
+	const S = 64
+	atlas := image.NewRGBA(image.Rect(0, 0, 4*S, S))
+	for i := range 4 {
+		// every tile is drawn in place into its part of the atlas
+		tile := atlas.SubImage(image.Rect(i*S, 0, (i+1)*S, S)).(*image.RGBA)
+		dc := gg.NewContextForRGBA(tile)
+		dc.DrawRegularPolygon(i+3, S/2, S/2, S/2-4, 0)
+		dc.SetRGB(float64(i)/3, 0.5, 1-float64(i)/3)
+		dc.Fill()
+	}
+	// a paletted copy for a GIF
+	im := image.NewPaletted(atlas.Rect, palette.Plan9)
+	dc := gg.NewContextForDrawImage(im)
+	dc.DrawImage(atlas, 0, 0)
+	if err := fio.SavePNG(GetFileName()+".png", im); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/FloatTech/gg"
+	"github.com/FloatTech/gg/factory"
+)
+
+func TestAvatarMasks(*testing.T) {
+	im, err := factory.Load("james-webb.png")
+	if err != nil {
+		panic(err)
+	}
+	dc := gg.NewContext(640, 220)
+	dc.SetRGB255(40, 44, 52)
+	dc.Clear()
+	// smooth circle avatar
+	circle := factory.Size(im, 200, 200).Circle(100)
+	dc.DrawImage(circle.Image(), 10, 10)
+	// rounded card with feathered corners
+	card := factory.Size(im, 200, 200).RoundCorners(40, 8)
+	dc.DrawImage(card.Image(), 220, 10)
+	// hexagon with feathered edges
+	hexagon := factory.Size(im, 200, 200)
+	var points []gg.Point
+	for i := range 6 {
+		a := gg.Radians(float64(60*i + 30))
+		points = append(points, gg.Point{X: 100 + 90*math.Cos(a), Y: 100 + 90*math.Sin(a)})
+	}
+	hexagon.MaskPolygon(points, 4)
+	dc.DrawImage(hexagon.Image(), 430, 10)
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
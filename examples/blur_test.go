@@ -0,0 +1,33 @@
+package main
+
+import (
+	"image"
+	"testing"
+
+	"github.com/FloatTech/gg"
+	"github.com/FloatTech/gg/fio"
+)
+
+func TestBlur(*testing.T) {
+	im, err := fio.LoadPNG("gopher.png")
+	if err != nil {
+		panic(err)
+	}
+	w, h := im.Bounds().Dx(), im.Bounds().Dy()
+	cx, cy := float64(w)/2, float64(h)/2
+	filters := []gg.Filter{
+		gg.Blur(4),
+		gg.BlurXY(8, 0),
+		gg.MotionBlur(24, 45),
+		gg.RadialBlur(cx, cy, 20),
+		gg.ZoomBlur(cx, cy, 0.2),
+	}
+	dc := gg.NewContext(w*len(filters), h)
+	for i, f := range filters {
+		dc.DrawImage(im, i*w, 0)
+		dc.ApplyFilterRect(f, image.Rect(i*w, 0, (i+1)*w, h))
+	}
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/FloatTech/gg"
+	"github.com/FloatTech/gg/factory"
+)
+
+func TestBorders(*testing.T) {
+	photo, err := factory.Load("james-webb.png")
+	if err != nil {
+		panic(err)
+	}
+	gopher, err := factory.Load("gopher.png")
+	if err != nil {
+		panic(err)
+	}
+	dc := gg.NewContext(660, 240)
+	dc.SetRGB255(40, 44, 52)
+	dc.Clear()
+	// avatar with a gradient ring
+	ring := gg.NewLinearGradient(0, 0, 200, 200)
+	ring.AddColorStop(0, color.RGBA{255, 200, 60, 255})
+	ring.AddColorStop(1, color.RGBA{220, 40, 140, 255})
+	avatar := factory.Size(photo, 200, 200).Circle(100).Border(8, ring)
+	dc.DrawImage(avatar.Image(), 20, 20)
+	// padded photo in a rounded frame
+	framed := factory.Size(photo, 160, 160).
+		Pad(20, 20, 20, 20, color.White).
+		RoundCorners(24, 0).
+		Border(4, gg.NewSolidPattern(color.RGBA{120, 120, 130, 255}))
+	dc.DrawImage(framed.Image(), 240, 20)
+	// sticker with an outer stroke
+	sticker := factory.Size(gopher, 0, 180).Outline(10, gg.NewSolidPattern(color.White))
+	dc.DrawImageAnchored(sticker.Image(), 550, 120, 0.5, 0.5)
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/FloatTech/gg"
+)
+
+func TestDeepColor(*testing.T) {
+	const W, H = 800, 100
+	dc := gg.NewContextRGBAF32(W, 3*H, true)
+	dc.SetRGB(0, 0, 0)
+	dc.Clear()
+	// a dark, shallow gradient bands visibly with 8 bits per channel
+	grad := gg.NewLinearGradient(0, 0, W, 0)
+	grad.AddColorStop(0, color.NRGBA{26, 26, 51, 255})
+	grad.AddColorStop(1, color.NRGBA{51, 38, 77, 255})
+	dc.SetFillStyle(grad)
+	dc.DrawRectangle(0, 0, W, H)
+	dc.Fill()
+	// overlapping translucent circles blend in linear light
+	dc.SetRGB(1, 1, 1)
+	dc.DrawRectangle(0, H, W, 2*H)
+	dc.Fill()
+	for i, c := range [][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}} {
+		dc.SetRGBA(c[0], c[1], c[2], 0.6)
+		dc.DrawCircle(float64(W/2-60+60*i), 2*H, 80)
+		dc.Fill()
+	}
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"image"
+	"testing"
+
+	"github.com/FloatTech/gg"
+	"github.com/FloatTech/gg/fio"
+)
+
+func TestFilter(*testing.T) {
+	im, err := fio.LoadPNG("gopher.png")
+	if err != nil {
+		panic(err)
+	}
+	w, h := im.Bounds().Dx(), im.Bounds().Dy()
+	filters := []gg.Filter{
+		gg.Sepia(1),
+		gg.HueRotate(120),
+		gg.Chain(gg.Grayscale(1), gg.Gamma(1.8)),
+		gg.Merge(gg.Chain(gg.Dilate(3, 3), gg.Invert(1)), nil),
+		gg.Convolve([]float64{
+			-1, -1, 0,
+			-1, 1, 1,
+			0, 1, 1,
+		}, 3),
+	}
+	dc := gg.NewContext(w*len(filters), h)
+	for i, f := range filters {
+		dc.DrawImage(im, i*w, 0)
+		dc.ApplyFilterRect(f, image.Rect(i*w, 0, (i+1)*w, h))
+	}
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"image"
+	"testing"
+
+	"github.com/FloatTech/gg"
+	"github.com/FloatTech/gg/fio"
+)
+
+func TestFrostedGlass(*testing.T) {
+	im, err := fio.LoadPNG("james-webb.png")
+	if err != nil {
+		panic(err)
+	}
+	dc := gg.NewContextForImage(im)
+	w, h := float64(dc.W()), float64(dc.H())
+	x, y, cw, ch := w/4, h/4, w/2, h/2
+	// blur only the panel behind the card with rounded corners
+	dc.DrawRoundedRectangle(x, y, cw, ch, 24)
+	dc.Clip()
+	dc.ApplyFilterRect(gg.Blur(12), image.Rect(int(x), int(y), int(x+cw), int(y+ch)))
+	dc.SetRGBA(1, 1, 1, 0.2)
+	dc.DrawRectangle(x, y, cw, ch)
+	dc.Fill()
+	dc.ResetClip()
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
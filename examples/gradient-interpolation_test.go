@@ -0,0 +1,39 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/FloatTech/gg"
+)
+
+func TestGradientInterpolation(*testing.T) {
+	const W, H = 600, 50
+	spaces := []gg.Interpolation{
+		gg.InterpolationPremultiplied,
+		gg.InterpolationStraight,
+		gg.InterpolationLinearRGB,
+		gg.InterpolationOKLab,
+		gg.InterpolationOKLCH,
+		gg.InterpolationHSL,
+	}
+	dc := gg.NewContext(W, H*len(spaces)*2)
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+	for i, space := range spaces {
+		for j, stops := range [][2]color.Color{{gg.Red, gg.Green}, {gg.Blue, color.Transparent}} {
+			y := float64((2*i + j) * H)
+			grad := gg.NewLinearGradient(0, 0, W, 0)
+			grad.AddColorStop(0, stops[0])
+			grad.AddColorStop(1, stops[1])
+			grad.SetInterpolation(space)
+			grad.SetDither(true)
+			dc.SetFillStyle(grad)
+			dc.DrawRectangle(0, y, W, H)
+			dc.Fill()
+		}
+	}
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/FloatTech/gg"
+)
+
+func TestGradientMesh(*testing.T) {
+	dc := gg.NewContext(900, 300)
+
+	// four corner card background
+	dc.SetFillStyle(gg.NewFourCornerGradient(20, 20, 280, 280,
+		color.RGBA{255, 94, 98, 255}, color.RGBA{255, 195, 113, 255},
+		color.RGBA{72, 198, 239, 255}, color.RGBA{111, 134, 214, 255}))
+	dc.DrawRoundedRectangle(20, 20, 260, 260, 24)
+	dc.Fill()
+
+	// a Coons patch with curved sides
+	mesh := gg.NewMeshGradient()
+	mesh.AddPatch([12]gg.Point{
+		{X: 330, Y: 40}, {X: 400, Y: 0}, {X: 500, Y: 80}, {X: 570, Y: 30},
+		{X: 530, Y: 120}, {X: 600, Y: 200}, {X: 560, Y: 270},
+		{X: 480, Y: 220}, {X: 420, Y: 300}, {X: 340, Y: 260},
+		{X: 380, Y: 180}, {X: 300, Y: 120},
+	}, [4]color.Color{gg.Red, gg.Yellow, gg.Blue, gg.Green})
+	dc.SetFillStyle(mesh)
+	dc.DrawRectangle(300, 0, 300, 300)
+	dc.Fill()
+
+	// freeform gradient from a few colored points
+	free := gg.NewFreeformGradient(2)
+	free.AddColorPoint(660, 60, gg.Red)
+	free.AddColorPoint(840, 80, gg.Yellow)
+	free.AddColorPoint(700, 240, gg.Blue)
+	free.AddColorPoint(860, 260, gg.Green)
+	free.AddColorPoint(760, 150, color.White)
+	dc.SetStrokeStyle(free)
+	dc.SetLineWidth(30)
+	dc.DrawCircle(750, 150, 110)
+	dc.Stroke()
+
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/FloatTech/gg"
+)
+
+func TestGradientSpread(*testing.T) {
+	const S = 200
+	dc := gg.NewContext(S*3, S*3)
+	for i, spread := range []gg.Spread{gg.SpreadPad, gg.SpreadRepeat, gg.SpreadReflect} {
+		x := float64(i * S)
+		gradients := []gg.Gradient{
+			gg.NewLinearGradient(x+80, 80, x+120, 120),
+			gg.NewRadialGradient(x+100, S+100, 10, x+100, S+100, 40),
+			gg.NewConicGradient(x+100, 2*S+100, 0),
+		}
+		for j, grad := range gradients {
+			grad.AddColorStop(0, gg.Blue)
+			if j == 2 {
+				grad.AddColorStop(1.0/6, gg.Yellow)
+			} else {
+				grad.AddColorStop(1, gg.Yellow)
+			}
+			grad.SetSpread(spread)
+			dc.SetFillStyle(grad)
+			dc.DrawRectangle(x, float64(j*S), S, S)
+			dc.Fill()
+		}
+	}
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/FloatTech/gg"
+	"github.com/FloatTech/gg/fio"
+)
+
+func TestHistogram(*testing.T) {
+	im, err := fio.LoadPNG("james-webb.png")
+	if err != nil {
+		panic(err)
+	}
+	dc := gg.NewContextForImage(im)
+	// bring out the detail in the shadows, then add some punch with an S curve
+	dc.ApplyFilter(gg.CLAHE(8, 3))
+	s := gg.ToneCurve(gg.Point{X: 0, Y: 0}, gg.Point{X: 0.25, Y: 0.2}, gg.Point{X: 0.75, Y: 0.8}, gg.Point{X: 1, Y: 1})
+	dc.ApplyFilter(gg.Curves(s, s, s))
+
+	// draw the luma histogram in the bottom left corner
+	h := dc.Histogram()
+	peak := 0
+	for _, n := range h.Luma {
+		peak = max(peak, n)
+	}
+	x, y, bh := 16.0, float64(dc.H())-16, 120.0
+	dc.SetRGBA(0, 0, 0, 0.6)
+	dc.DrawRectangle(x, y-bh, 256, bh)
+	dc.Fill()
+	dc.SetRGB(1, 1, 1)
+	for v, n := range h.Luma {
+		dc.DrawRectangle(x+float64(v), y, 1, -bh*float64(n)/float64(peak))
+	}
+	dc.Fill()
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/FloatTech/gg"
+	"github.com/FloatTech/gg/fio"
+)
+
+func TestImageFit(*testing.T) {
+	im, err := fio.LoadPNG("james-webb.png")
+	if err != nil {
+		panic(err)
+	}
+	const W, H = 300, 200
+	fits := []gg.ImageFit{gg.FitFill, gg.FitContain, gg.FitCover, gg.FitNone}
+	dc := gg.NewContext(2*W+60, 2*H+60)
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+	for i, fit := range fits {
+		x := 20 + float64(i%2)*(W+20)
+		y := 20 + float64(i/2)*(H+20)
+		dc.Push()
+		if fit == gg.FitNone {
+			// fit modes follow the current matrix, crop included
+			dc.RotateAbout(gg.Radians(10), x+W/2, y+H/2)
+		}
+		dc.DrawImageFit(im, x, y, W, H, fit)
+		dc.DrawRectangle(x, y, W, H)
+		dc.Pop()
+	}
+	dc.SetRGB(0, 0, 0)
+	dc.Stroke()
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
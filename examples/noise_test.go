@@ -0,0 +1,34 @@
+package main
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/FloatTech/gg"
+)
+
+func TestNoise(*testing.T) {
+	const S = 200
+	dc := gg.NewContext(S*4, S*2)
+	noises := []gg.Noise{gg.NoisePerlin, gg.NoiseSimplex, gg.NoiseValue, gg.NoiseWorley}
+	for i, noise := range noises {
+		for j, octaves := range []int{1, 5} {
+			grad := gg.NewNoisePattern(noise, 40, octaves, 42)
+			grad.AddColorStop(0, color.RGBA{10, 20, 80, 255})
+			grad.AddColorStop(0.5, color.RGBA{40, 160, 200, 255})
+			grad.AddColorStop(1, color.RGBA{240, 250, 255, 255})
+			dc.SetFillStyle(grad)
+			dc.DrawRectangle(float64(i*S), float64(j*S), S, S)
+			dc.Fill()
+		}
+	}
+	dc.SetFillStyle(gg.NewCheckerboardPattern(10, color.Black, color.Transparent))
+	dc.DrawCircle(S/2, S/2, 40)
+	dc.Fill()
+	dc.SetFillStyle(gg.NewStripePattern(8, gg.Radians(45), gg.Red, gg.Yellow))
+	dc.DrawCircle(S*3/2, S/2, 40)
+	dc.Fill()
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/FloatTech/gg"
+	"github.com/FloatTech/gg/fio"
+)
+
+func TestPatternSurface(*testing.T) {
+	im, err := fio.LoadPNG("gopher.png")
+	if err != nil {
+		panic(err)
+	}
+	dc := gg.NewContext(800, 400)
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+
+	// mirrored tiles starting at the corner of the rectangle
+	pattern := gg.NewSurfacePattern(im, gg.RepeatBoth)
+	pattern.SetOrigin(20, 20)
+	pattern.SetMirror(true)
+	dc.SetFillStyle(pattern)
+	dc.DrawRectangle(20, 20, 360, 360)
+	dc.Fill()
+
+	// an enlarged and rotated texture, filtered bilinearly
+	pattern = gg.NewSurfacePattern(im, gg.RepeatBoth)
+	pattern.SetScaleStyle(gg.BiLinear)
+	dc.RotateAbout(gg.Radians(-15), 600, 200)
+	dc.Translate(600, 200)
+	dc.Scale(1.6, 1.6)
+	dc.SetFillStyle(pattern)
+	dc.DrawCircle(0, 0, 110)
+	dc.Fill()
+
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
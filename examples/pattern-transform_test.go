@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/FloatTech/gg"
+	"github.com/FloatTech/gg/fio"
+)
+
+func TestPatternTransform(*testing.T) {
+	im, err := fio.LoadPNG("gopher.png")
+	if err != nil {
+		panic(err)
+	}
+	dc := gg.NewContext(600, 300)
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+
+	// the pattern is rotated and scaled together with the square
+	dc.Push()
+	dc.RotateAbout(gg.Radians(30), 150, 150)
+	dc.Translate(60, 60)
+	dc.Scale(0.5, 0.5)
+	dc.SetFillStyle(gg.NewSurfacePattern(im, gg.RepeatBoth))
+	dc.DrawRectangle(0, 0, 360, 360)
+	dc.Fill()
+	dc.Pop()
+
+	// a radial gradient squashed into an ellipse
+	dc.Push()
+	dc.Translate(450, 150)
+	dc.Rotate(gg.Radians(-20))
+	dc.Scale(1, 0.6)
+	grad := gg.NewRadialGradient(0, 0, 0, 0, 0, 50)
+	grad.AddColorStop(0, gg.Yellow)
+	grad.AddColorStop(1, gg.Red)
+	grad.SetSpread(gg.SpreadReflect)
+	dc.SetFillStyle(grad)
+	dc.DrawCircle(0, 0, 140)
+	dc.Fill()
+	dc.Pop()
+
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
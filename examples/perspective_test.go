@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/FloatTech/gg"
+	"github.com/FloatTech/gg/factory"
+)
+
+func TestPerspective(*testing.T) {
+	photo, err := factory.Load("james-webb.png")
+	if err != nil {
+		panic(err)
+	}
+	dc := gg.NewContext(640, 360)
+	dc.SetRGB255(226, 230, 236)
+	dc.Clear()
+	// a monitor seen from the side
+	screen := [4]gg.Point{{X: 150, Y: 60}, {X: 470, Y: 30}, {X: 470, Y: 290}, {X: 150, Y: 250}}
+	dc.SetRGB255(30, 32, 36)
+	dc.MoveTo(screen[0].X-12, screen[0].Y-10)
+	dc.LineTo(screen[1].X+12, screen[1].Y-12)
+	dc.LineTo(screen[2].X+12, screen[2].Y+12)
+	dc.LineTo(screen[3].X-12, screen[3].Y+10)
+	dc.Fill()
+	dc.SetScaleStyle(gg.Lanczos2)
+	dc.DrawImageQuad(photo, screen)
+	// the same warp on a Factory, and a rotated thumbnail
+	sign := factory.Size(photo, 160, 160).
+		Perspective(120, 120, [4]gg.Point{{X: 10, Y: 20}, {X: 110, Y: 0}, {X: 100, Y: 120}, {X: 0, Y: 100}}, gg.BiLinear)
+	dc.DrawImage(sign.Image(), 500, 30)
+	tilted := factory.Size(photo, 100, 100).Rotate(15, gg.BiLinear)
+	dc.DrawImageAnchored(tilted.Image(), 560, 270, 0.5, 0.5)
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
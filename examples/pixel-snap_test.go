@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/FloatTech/gg"
+)
+
+func TestPixelSnap(*testing.T) {
+	const W = 1000
+	const H = 1000
+	const Minor = 10
+	const Major = 100
+
+	dc := gg.NewContext(W, H)
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+
+	// snap lines to pixel centers instead of offsetting them by 0.5 by hand
+	dc.SetPixelSnap(true)
+
+	// minor grid
+	for x := Minor; x < W; x += Minor {
+		dc.DrawLine(float64(x), 0, float64(x), H)
+	}
+	for y := Minor; y < H; y += Minor {
+		dc.DrawLine(0, float64(y), W, float64(y))
+	}
+	dc.SetLineWidth(1)
+	dc.SetRGBA(0, 0, 0, 0.25)
+	dc.Stroke()
+
+	// major grid, drawn in a scaled coordinate system with hairlines that
+	// stay one device pixel wide
+	dc.Push()
+	dc.Scale(Major, Major)
+	dc.SetHairline(true)
+	for i := 1; i < W/Major; i++ {
+		dc.DrawLine(float64(i), 0, float64(i), H/Major)
+		dc.DrawLine(0, float64(i), W/Major, float64(i))
+	}
+	dc.SetRGBA(0, 0, 0, 0.5)
+	dc.Stroke()
+	dc.Pop()
+
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/FloatTech/gg"
+)
+
+func TestShapes(*testing.T) {
+	const S = 600
+	dc := gg.NewContext(S, S)
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+	dc.SetLineWidth(4)
+
+	draw := func(r, g, b float64) {
+		dc.SetRGB(r, g, b)
+		dc.FillPreserve()
+		dc.SetRGB(0, 0, 0)
+		dc.Stroke()
+	}
+
+	dc.DrawRoundedRectangleCorners(30, 30, 160, 120, 0, 20, 60, 10)
+	draw(1, 0.8, 0.3)
+	dc.DrawStar(5, 310, 90, 70, 28, 0)
+	draw(1, 0.9, 0)
+	dc.DrawRoundedStar(7, 500, 90, 70, 35, 0, 8)
+	draw(0.9, 0.4, 0.6)
+
+	dc.DrawRoundedRegularPolygon(6, 110, 300, 80, 0, 16)
+	draw(0.3, 0.7, 1)
+	dc.DrawSquircle(310, 300, 80)
+	draw(0.5, 0.9, 0.5)
+	dc.DrawSuperellipse(500, 300, 70, 50, 0.8)
+	draw(0.7, 0.6, 1)
+
+	// a pie chart and a donut chart
+	values := []float64{0.35, 0.25, 0.2, 0.2}
+	colors := [][3]float64{{1, 0.4, 0.4}, {0.4, 0.8, 0.4}, {0.4, 0.6, 1}, {1, 0.8, 0.3}}
+	a := -gg.Radians(90)
+	for i, v := range values {
+		b := a + v*2*math.Pi
+		dc.DrawSector(150, 480, 90, a, b)
+		draw(colors[i][0], colors[i][1], colors[i][2])
+		dc.DrawAnnularSector(430, 480, 50, 90, a, b)
+		draw(colors[i][0], colors[i][1], colors[i][2])
+		a = b
+	}
+
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
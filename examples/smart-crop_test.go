@@ -0,0 +1,40 @@
+package main
+
+import (
+	"image"
+	"testing"
+
+	"github.com/FloatTech/gg"
+	"github.com/FloatTech/gg/fio"
+)
+
+func TestSmartCrop(*testing.T) {
+	im, err := fio.LoadPNG("james-webb.png")
+	if err != nil {
+		panic(err)
+	}
+	// a wide banner and a tall card from the same picture, next to a
+	// blind center crop
+	dc := gg.NewContext(640, 480)
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+	banner := gg.SmartCrop(im, 3, 1)
+	dc.DrawImageRect(im, banner, 10, 10, 620, 620.0/3)
+	card := gg.SmartCrop(im, 2, 3)
+	dc.DrawImageRect(im, card, 10, 230, 160, 240)
+	focal := gg.FocalRect(im.Bounds(), 2, 3, 0.85, 0.8)
+	dc.DrawImageRect(im, focal, 180, 230, 160, 240)
+	center := gg.FocalRect(im.Bounds(), 2, 3, 0.5, 0.5)
+	dc.DrawImageRect(im, center, 350, 230, 160, 240)
+	// outline the crops on a thumbnail of the whole picture
+	dc.DrawImageRect(im, im.Bounds(), 520, 230, 110, 110)
+	s := 110.0 / float64(im.Bounds().Dx())
+	for _, r := range []image.Rectangle{card, focal, center} {
+		dc.DrawRectangle(520+float64(r.Min.X)*s, 230+float64(r.Min.Y)*s, float64(r.Dx())*s, float64(r.Dy())*s)
+	}
+	dc.SetRGB(1, 0, 0)
+	dc.Stroke()
+	if err := dc.SavePNG(GetFileName() + ".png"); err != nil {
+		panic(err)
+	}
+}
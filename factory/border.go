@@ -0,0 +1,184 @@
+package factory
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/FloatTech/gg"
+	"github.com/FloatTech/gg/internal/pixop"
+)
+
+// Pad 扩展画布 在四周分别加上 top, right, bottom, left 像素并以 fill 填充
+func (dst *Factory) Pad(top, right, bottom, left int, fill color.Color) *Factory {
+	sz := dst.im.Bounds().Size()
+	nf := NewFactoryBG(max(0, left+sz.X+right), max(0, top+sz.Y+bottom), fill)
+	w := 4 * sz.X
+	for y := range sz.Y {
+		if y+top < 0 || y+top >= nf.im.Rect.Dy() {
+			continue
+		}
+		srow := dst.im.Pix[y*dst.im.Stride : y*dst.im.Stride+w]
+		drow := nf.im.Pix[(y+top)*nf.im.Stride : (y+top+1)*nf.im.Stride]
+		for x := max(0, -left); x < sz.X && x+left < nf.im.Rect.Dx(); x++ {
+			copy(drow[4*(x+left):4*(x+left)+4], srow[4*x:4*x+4])
+		}
+	}
+	return nf
+}
+
+// Extend 扩展画布 在四周分别加上 top, right, bottom, left 像素并重复边缘像素
+func (dst *Factory) Extend(top, right, bottom, left int) *Factory {
+	sz := dst.im.Bounds().Size()
+	nf := NewFactoryBG(max(0, left+sz.X+right), max(0, top+sz.Y+bottom), color.Transparent)
+	if sz.X == 0 || sz.Y == 0 {
+		return nf
+	}
+	b := nf.im.Rect
+	for y := range b.Dy() {
+		sy := max(0, min(sz.Y-1, y-top))
+		srow := dst.im.Pix[sy*dst.im.Stride:]
+		drow := nf.im.Pix[y*nf.im.Stride:]
+		for x := range b.Dx() {
+			sx := max(0, min(sz.X-1, x-left))
+			copy(drow[4*x:4*x+4], srow[4*sx:4*sx+4])
+		}
+	}
+	return nf
+}
+
+// Border 描边 沿图像不透明部分(alpha ≥ 128)的轮廓向内绘制宽度为 width 的边框，边缘抗锯齿
+//
+// p 可为纯色或渐变等图案，使用图像的坐标。图像边界同样视为轮廓，因此也可用于矩形或圆角画框。在原图像上修改
+func (dst *Factory) Border(width float64, p gg.Pattern) *Factory {
+	b := dst.im.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if width <= 0 || w == 0 || h == 0 {
+		return dst
+	}
+	// 距离最近的透明像素，四周视为透明
+	d := distanceSquared(w+2, h+2, func(x, y int) bool {
+		return x == 0 || y == 0 || x > w || y > h || dst.im.Pix[(y-1)*dst.im.Stride+4*(x-1)+3] < 128
+	})
+	pixop.Rows(gg.Workers(), h, func(y0, y1 int) {
+		for y := y0; y < y1; y++ {
+			for x := range w {
+				cov := coverage(width, d[(y+1)*(w+2)+x+1])
+				px := dst.im.Pix[y*dst.im.Stride+4*x : y*dst.im.Stride+4*x+4]
+				if cov == 0 || px[3] == 0 {
+					continue
+				}
+				pr, pg, pb, pa := p.ColorAt(x+b.Min.X, y+b.Min.Y).RGBA()
+				k := 1 - float64(pa)/0xffff*cov
+				for c, v := range [3]uint32{pr, pg, pb} {
+					px[c] = uint8(float64(v)/0x101*cov + float64(px[c])*k + 0.5)
+				}
+			}
+		}
+	})
+	return dst
+}
+
+// Outline 外描边 沿图像不透明部分(alpha ≥ 128)的轮廓向外绘制宽度为 width 的描边，适用于透明贴纸
+//
+// 画布四周各扩展 width 像素(向上取整)再加 1 像素，为抗锯齿的边缘留出空间。p 可为纯色或渐变等图案，使用新图像的坐标
+func (dst *Factory) Outline(width float64, p gg.Pattern) *Factory {
+	if width <= 0 {
+		return dst.Pad(0, 0, 0, 0, color.Transparent)
+	}
+	pad := int(math.Ceil(width)) + 1
+	nf := dst.Pad(pad, pad, pad, pad, color.Transparent)
+	im := nf.im
+	w, h := im.Rect.Dx(), im.Rect.Dy()
+	// 距离最近的不透明像素
+	d := distanceSquared(w, h, func(x, y int) bool {
+		return im.Pix[y*im.Stride+4*x+3] >= 128
+	})
+	pixop.Rows(gg.Workers(), h, func(y0, y1 int) {
+		for y := y0; y < y1; y++ {
+			for x := range w {
+				cov := coverage(width, d[y*w+x])
+				px := im.Pix[y*im.Stride+4*x : y*im.Stride+4*x+4]
+				if cov == 0 || px[3] == 255 {
+					continue
+				}
+				// 原图像叠加在描边之上
+				pr, pg, pb, pa := p.ColorAt(x, y).RGBA()
+				a := float64(px[3]) / 255
+				la := float64(pa) / 0xffff * cov
+				oa := a + la*(1-a)
+				if oa == 0 {
+					continue
+				}
+				for c, v := range [3]uint32{pr, pg, pb} {
+					px[c] = uint8((float64(px[c])*a+float64(v)/0x101*cov*(1-a))/oa + 0.5)
+				}
+				px[3] = uint8(oa*255 + 0.5)
+			}
+		}
+	})
+	return nf
+}
+
+// coverage 返回与轮廓的平方距离为 d2 的像素被宽度为 width 的描边覆盖的比例
+func coverage(width, d2 float64) float64 {
+	// 像素中心到轮廓的距离约为到最近像素中心的距离减去半个像素
+	return max(0, min(1, width+1-math.Sqrt(d2)))
+}
+
+// distanceSquared 返回 w x h 网格中每个点到最近的满足 target 的点的平方欧氏距离
+//
+// 参见 Felzenszwalb 与 Huttenlocher, "Distance Transforms of Sampled Functions"
+func distanceSquared(w, h int, target func(x, y int) bool) []float64 {
+	const inf = 1e20
+	d := make([]float64, w*h)
+	for y := range h {
+		for x := range w {
+			if !target(x, y) {
+				d[y*w+x] = inf
+			}
+		}
+	}
+	n := max(w, h)
+	f, out := make([]float64, n), make([]float64, n)
+	v, z := make([]int, n), make([]float64, n+1)
+	for x := range w {
+		for y := range h {
+			f[y] = d[y*w+x]
+		}
+		distance1D(f[:h], out[:h], v, z)
+		for y := range h {
+			d[y*w+x] = out[y]
+		}
+	}
+	for y := range h {
+		copy(f, d[y*w:(y+1)*w])
+		distance1D(f[:w], out[:w], v, z)
+		copy(d[y*w:(y+1)*w], out[:w])
+	}
+	return d
+}
+
+// distance1D 计算一维平方距离变换，v 与 z 为临时空间
+func distance1D(f, d []float64, v []int, z []float64) {
+	n := len(f)
+	k := 0
+	v[0] = 0
+	z[0], z[1] = math.Inf(-1), math.Inf(1)
+	for q := 1; q < n; q++ {
+		s := ((f[q] + float64(q*q)) - (f[v[k]] + float64(v[k]*v[k]))) / float64(2*q-2*v[k])
+		for s <= z[k] {
+			k--
+			s = ((f[q] + float64(q*q)) - (f[v[k]] + float64(v[k]*v[k]))) / float64(2*q-2*v[k])
+		}
+		k++
+		v[k] = q
+		z[k], z[k+1] = s, math.Inf(1)
+	}
+	k = 0
+	for q := range n {
+		for z[k+1] < float64(q) {
+			k++
+		}
+		d[q] = float64((q-v[k])*(q-v[k])) + f[v[k]]
+	}
+}
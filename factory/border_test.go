@@ -0,0 +1,146 @@
+package factory
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"github.com/FloatTech/gg"
+)
+
+var (
+	red   = color.NRGBA{255, 0, 0, 255}
+	green = color.NRGBA{0, 255, 0, 255}
+	white = color.NRGBA{255, 255, 255, 255}
+)
+
+func TestDistanceSquared(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	const w, h = 17, 13
+	target := make([]bool, w*h)
+	target[r.Intn(w*h)] = true
+	for i := range target {
+		target[i] = target[i] || r.Intn(10) == 0
+	}
+	d := distanceSquared(w, h, func(x, y int) bool { return target[y*w+x] })
+	for y := range h {
+		for x := range w {
+			want := -1
+			for ty := range h {
+				for tx := range w {
+					if dd := (x-tx)*(x-tx) + (y-ty)*(y-ty); target[ty*w+tx] && (want < 0 || dd < want) {
+						want = dd
+					}
+				}
+			}
+			if got := d[y*w+x]; got != float64(want) {
+				t.Fatalf("(%d, %d): expected %d, got %v", x, y, want, got)
+			}
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	src := NewFactoryBG(4, 3, red)
+	src.Image().SetNRGBA(0, 0, green)
+	f := src.Pad(1, 2, 3, 4, white)
+	im := f.Image()
+	if im.Rect != image.Rect(0, 0, 10, 7) {
+		t.Fatalf("expected a 10x7 image, got %v", im.Rect)
+	}
+	for _, tc := range []struct {
+		x, y int
+		want color.NRGBA
+	}{
+		{0, 0, white}, {3, 1, white}, {4, 0, white}, {8, 1, white}, {4, 4, white},
+		{4, 1, green}, {5, 1, red}, {7, 3, red},
+	} {
+		if got := im.NRGBAAt(tc.x, tc.y); got != tc.want {
+			t.Errorf("pixel (%d, %d): expected %v, got %v", tc.x, tc.y, tc.want, got)
+		}
+	}
+	// negative margins crop
+	im = src.Pad(-1, 0, 0, -1, white).Image()
+	if im.Rect != image.Rect(0, 0, 3, 2) {
+		t.Fatalf("expected a 3x2 image, got %v", im.Rect)
+	}
+	for y := range 2 {
+		for x := range 3 {
+			if got := im.NRGBAAt(x, y); got != red {
+				t.Errorf("pixel (%d, %d): expected %v, got %v", x, y, red, got)
+			}
+		}
+	}
+	if im := src.Pad(-2, 0, -2, 0, white).Image(); !im.Rect.Empty() {
+		t.Errorf("expected an empty image, got %v", im.Rect)
+	}
+}
+
+func TestExtend(t *testing.T) {
+	src := NewFactoryBG(3, 2, red)
+	src.Image().SetNRGBA(0, 0, green)
+	im := src.Extend(2, 1, 0, 3).Image()
+	if im.Rect != image.Rect(0, 0, 7, 4) {
+		t.Fatalf("expected a 7x4 image, got %v", im.Rect)
+	}
+	for _, tc := range []struct {
+		x, y int
+		want color.NRGBA
+	}{
+		// the corner pixel fills the corner and repeats along its edges
+		{0, 0, green}, {3, 0, green}, {0, 2, green}, {3, 2, green},
+		{4, 0, red}, {0, 3, red}, {6, 0, red}, {6, 3, red},
+	} {
+		if got := im.NRGBAAt(tc.x, tc.y); got != tc.want {
+			t.Errorf("pixel (%d, %d): expected %v, got %v", tc.x, tc.y, tc.want, got)
+		}
+	}
+}
+
+func TestBorder(t *testing.T) {
+	// an opaque square in the right half of a transparent image
+	src := NewFactoryBG(30, 20, color.Transparent)
+	for y := range 20 {
+		for x := 10; x < 30; x++ {
+			src.Image().SetNRGBA(x, y, white)
+		}
+	}
+	im := src.Border(3, gg.NewSolidPattern(red)).Image()
+	for x, want := range []color.NRGBA{{}, {}, red, red, red, white, white} {
+		if got := im.NRGBAAt(x+8, 10); got != want {
+			t.Errorf("pixel (%d, 10): expected %v, got %v", x+8, want, got)
+		}
+	}
+	// the image bounds are an outline too
+	if got := im.NRGBAAt(29, 10); got != red {
+		t.Errorf("expected %v on the right edge, got %v", red, got)
+	}
+	// a fractional width blends the last pixel
+	im = NewFactoryBG(10, 10, white).Border(1.5, gg.NewSolidPattern(red)).Image()
+	if got, want := im.NRGBAAt(1, 5), (color.NRGBA{255, 128, 128, 255}); got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestOutline(t *testing.T) {
+	im := NewFactoryBG(10, 10, red).Outline(3, gg.NewSolidPattern(white)).Image()
+	if im.Rect != image.Rect(0, 0, 18, 18) {
+		t.Fatalf("expected an 18x18 image, got %v", im.Rect)
+	}
+	// the stroke fades out inside the canvas
+	for x, want := range []color.NRGBA{{}, white, white, white, red} {
+		if got := im.NRGBAAt(x, 9); got != want {
+			t.Errorf("pixel (%d, 9): expected %v, got %v", x, want, got)
+		}
+	}
+	im = NewFactoryBG(10, 10, red).Outline(2.5, gg.NewSolidPattern(white)).Image()
+	if im.Rect != image.Rect(0, 0, 18, 18) {
+		t.Fatalf("expected an 18x18 image, got %v", im.Rect)
+	}
+	for x, want := range []uint8{0, 128, 255, 255, 255} {
+		if got := im.NRGBAAt(x, 9).A; got != want {
+			t.Errorf("pixel (%d, 9): expected alpha %d, got %d", x, want, got)
+		}
+	}
+}
@@ -3,19 +3,15 @@ package factory
 import (
 	"image/color"
 
+	"github.com/FloatTech/gg"
+	"github.com/FloatTech/gg/internal/pixop"
 	"github.com/disintegration/imaging"
 )
 
-// float64转uint8
-func floatUint8(a float64) uint8 {
-	b := int64(a + 0.5)
-	if b > 255 {
-		return 255
-	}
-	if b > 0 {
-		return uint8(b)
-	}
-	return 0
+// apply 在原图像上对每个像素并行执行 op
+func (dst *Factory) apply(op pixop.Op) *Factory {
+	pixop.Apply(gg.Workers(), dst.im.Pix, dst.im.Stride, dst.im.Rect.Dx(), dst.im.Rect.Dy(), op)
+	return dst
 }
 
 // AdjustBrightness 亮度(-100, 100)
@@ -53,37 +49,49 @@ func (dst *Factory) Blur(a float64) *Factory {
 	}
 }
 
-// Grayscale 灰度
+// Grayscale 灰度 按 Rec. 601 加权，与按 Rec. 709 加权的 gg.Grayscale 滤镜结果略有不同
 func (dst *Factory) Grayscale() *Factory {
-	b := dst.im.Bounds()
-	for y1 := b.Min.Y; y1 <= b.Max.Y; y1++ {
-		for x1 := b.Min.X; x1 <= b.Max.X; x1++ {
-			a := dst.im.At(x1, y1)
-			c := color.NRGBAModel.Convert(a).(color.NRGBA)
-			f := 0.299*float64(c.R) + 0.587*float64(c.G) + 0.114*float64(c.B)
-			c.R = floatUint8(f)
-			c.G = floatUint8(f)
-			c.B = floatUint8(f)
-			dst.im.Set(x1, y1, c)
-		}
-	}
-	return dst
+	return dst.apply(pixop.Gray(pixop.Rec601))
 }
 
 // Invert 反色
 func (dst *Factory) Invert() *Factory {
-	b := dst.im.Bounds()
-	for y1 := b.Min.Y; y1 <= b.Max.Y; y1++ {
-		for x1 := b.Min.X; x1 <= b.Max.X; x1++ {
-			a := dst.im.At(x1, y1)
-			c := color.NRGBAModel.Convert(a).(color.NRGBA)
-			c.R = 255 - c.R
-			c.G = 255 - c.G
-			c.B = 255 - c.B
-			dst.im.Set(x1, y1, c)
-		}
-	}
-	return dst
+	return dst.apply(pixop.Invert().Op())
+}
+
+// Threshold 二值化 亮度(同 Grayscale 按 Rec. 601 加权)不低于 level 的像素变白，其余变黑
+func (dst *Factory) Threshold(level uint8) *Factory {
+	return dst.apply(pixop.Threshold(level, pixop.Rec601))
+}
+
+// Posterize 色调分离 每个颜色分量保留 levels 个色阶
+func (dst *Factory) Posterize(levels int) *Factory {
+	return dst.apply(pixop.Posterize(levels).Op())
+}
+
+// Duotone 双色调 将亮度(同 Grayscale 按 Rec. 601 加权)映射到从 dark 到 light 的渐变色
+func (dst *Factory) Duotone(dark, light color.Color) *Factory {
+	return dst.apply(pixop.Duotone(pixop.RGB8(dark), pixop.RGB8(light), pixop.Rec601))
+}
+
+// Sepia 怀旧(老照片)
+func (dst *Factory) Sepia() *Factory {
+	return dst.apply(pixop.Matrix(pixop.SepiaMatrix(1)))
+}
+
+// SwapChannels 通道交换 将红、绿、蓝与 alpha 分量分别设为原像素的通道 r、g、b 与 a
+func (dst *Factory) SwapChannels(r, g, b, a gg.Channel) *Factory {
+	return dst.apply(pixop.Swap(int(r), int(g), int(b), int(a)))
+}
+
+// Levels 色阶 将 [inBlack, inWhite] 映射到 [outBlack, outWhite]，中间应用伽马校正 gamma
+func (dst *Factory) Levels(inBlack, inWhite uint8, gamma float64, outBlack, outWhite uint8) *Factory {
+	return dst.apply(pixop.Levels(inBlack, inWhite, gamma, outBlack, outWhite).Op())
+}
+
+// Curves 曲线 将红、绿、蓝分量分别通过查找表 r、g、b 映射，nil 表示不变
+func (dst *Factory) Curves(r, g, b *[256]uint8) *Factory {
+	return dst.apply(pixop.Curves(r, g, b).Op())
 }
 
 // Relief 浮雕
@@ -100,3 +108,40 @@ func (dst *Factory) Relief() *Factory {
 		),
 	}
 }
+
+// Filter 应用滤镜 f，参见 gg.Filter
+func (dst *Factory) Filter(f gg.Filter) *Factory {
+	return &Factory{
+		im: f.Filter(dst.im),
+	}
+}
+
+// Histogram 直方图，参见 gg.Histogram
+func (dst *Factory) Histogram() *gg.Histogram {
+	return gg.NewHistogram(dst.im)
+}
+
+// AutoContrast 自动对比度 clip 为两端忽略的像素比例，例如 0.005
+func (dst *Factory) AutoContrast(clip float64) *Factory {
+	return dst.Filter(gg.AutoContrast(clip))
+}
+
+// AutoLevels 自动色阶 分别拉伸红、绿、蓝分量，clip 为两端忽略的像素比例，例如 0.005
+func (dst *Factory) AutoLevels(clip float64) *Factory {
+	return dst.Filter(gg.AutoLevels(clip))
+}
+
+// AutoWhiteBalance 自动白平衡(灰度世界)
+func (dst *Factory) AutoWhiteBalance() *Factory {
+	return dst.Filter(gg.AutoWhiteBalance())
+}
+
+// Equalize 直方图均衡化
+func (dst *Factory) Equalize() *Factory {
+	return dst.Filter(gg.Equalize())
+}
+
+// CLAHE 限制对比度自适应直方图均衡化 分为 tiles x tiles 个区域，clipLimit 例如 2 到 4
+func (dst *Factory) CLAHE(tiles int, clipLimit float64) *Factory {
+	return dst.Filter(gg.CLAHE(tiles, clipLimit))
+}
@@ -109,22 +109,16 @@ func (dst *Factory) InsertBottomC(im image.Image, w, h, x, y int) *Factory {
 	return dst
 }
 
-// Circle 获取圆图
+// Circle 获取圆图 边缘抗锯齿
 func (dst *Factory) Circle(r int) *Factory {
 	sz := dst.im.Bounds().Size()
 	if r == 0 {
 		r = sz.Y / 2
 	}
 	dst = dst.Reshape(2*r, 2*r)
-	b := dst.im.Bounds()
-	for y1 := b.Min.Y; y1 < b.Max.Y; y1++ {
-		for x1 := b.Min.X; x1 < b.Max.X; x1++ {
-			if (x1-r)*(x1-r)+(y1-r)*(y1-r) > r*r {
-				dst.im.Set(x1, y1, color.NRGBA{0, 0, 0, 0})
-			}
-		}
-	}
-	return dst
+	return dst.Mask(func(dc *gg.Context) {
+		dc.DrawCircle(float64(r), float64(r), float64(r))
+	}, 0)
 }
 
 // Clip 剪取方图
@@ -133,32 +127,31 @@ func (dst *Factory) Clip(w, h, x, y int) *Factory {
 	return dst
 }
 
-// ClipCircleFix 裁取圆图
+// SmartCrop 智能裁剪 按边缘、显著性与肤色选取宽高比为 w:h 的最佳区域并缩放到 w x h，参见 gg.SmartCrop
+func (dst *Factory) SmartCrop(w, h int) *Factory {
+	return Size(dst.im.SubImage(gg.SmartCrop(dst.im, w, h)), w, h)
+}
+
+// FocalFit 焦点覆盖适应 裁取以焦点 (fx, fy) 为中心、宽高比为 w:h 的最大区域并缩放到 w x h
+//
+// fx, fy 为相对于图像的 [0, 1] 坐标，参见 gg.FocalRect
+func (dst *Factory) FocalFit(w, h int, fx, fy float64) *Factory {
+	return Size(dst.im.SubImage(gg.FocalRect(dst.im.Rect, w, h, fx, fy)), w, h)
+}
+
+// ClipCircleFix 裁取圆图 边缘抗锯齿
 func (dst *Factory) ClipCircleFix(x, y, r int) *Factory {
 	dst = dst.Clip(2*r, 2*r, x-r, y-r)
-	b := dst.im.Bounds()
-	for y1 := b.Min.Y; y1 < b.Max.Y; y1++ {
-		for x1 := b.Min.X; x1 < b.Max.X; x1++ {
-			if (x1-x)*(x1-x)+(y1-y)*(y1-y) > r*r {
-				dst.im.Set(x1, y1, color.NRGBA{0, 0, 0, 0})
-			}
-		}
-	}
-	return dst
+	return dst.Mask(func(dc *gg.Context) {
+		dc.DrawCircle(float64(x), float64(y), float64(r))
+	}, 0)
 }
 
-// ClipCircle 扣取圆
+// ClipCircle 扣取圆 边缘抗锯齿
 func (dst *Factory) ClipCircle(x, y, r int) *Factory {
-	//  dc := dst.Clip(x-r, y-r, 2*r, 2*r)
-	b := dst.im.Bounds()
-	for y1 := b.Min.Y; y1 < b.Max.Y; y1++ {
-		for x1 := b.Min.X; x1 < b.Max.X; x1++ {
-			if (x1-x)*(x1-x)+(y1-y)*(y1-y) <= r*r {
-				dst.im.Set(x1, y1, color.NRGBA{0, 0, 0, 0})
-			}
-		}
-	}
-	return dst
+	return dst.MaskOut(func(dc *gg.Context) {
+		dc.DrawCircle(float64(x), float64(y), float64(r))
+	}, 0)
 }
 
 // InsertText 插入文本
@@ -0,0 +1,58 @@
+package factory
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// subjectFactory returns a flat gray 300x100 image with a textured skin
+// colored subject in the right part of it.
+func subjectFactory() *Factory {
+	f := NewFactoryBG(300, 100, color.NRGBA{90, 100, 110, 255})
+	for y := 30; y < 70; y++ {
+		for x := 220; x < 280; x++ {
+			c := color.NRGBA{220, 160, 125, 255}
+			if (x/4+y/4)%2 == 0 {
+				c = color.NRGBA{150, 105, 80, 255}
+			}
+			f.Image().SetNRGBA(x, y, c)
+		}
+	}
+	return f
+}
+
+// checkSubject reports whether the 50x50 image im shows the subject of
+// subjectFactory in its middle and the gray background in its corners.
+func checkSubject(t *testing.T, name string, im *image.NRGBA) {
+	t.Helper()
+	if im.Rect != image.Rect(0, 0, 50, 50) {
+		t.Fatalf("%s: expected a 50x50 image, got %v", name, im.Rect)
+	}
+	for _, p := range []image.Point{{25, 25}, {12, 18}, {37, 32}} {
+		if c := im.NRGBAAt(p.X, p.Y); c.R <= c.B {
+			t.Errorf("%s: pixel %v: expected the subject, got %v", name, p, c)
+		}
+	}
+	for _, p := range []image.Point{{1, 1}, {48, 48}} {
+		if c := im.NRGBAAt(p.X, p.Y); c != (color.NRGBA{90, 100, 110, 255}) {
+			t.Errorf("%s: pixel %v: expected the background, got %v", name, p, c)
+		}
+	}
+}
+
+func TestSmartCrop(t *testing.T) {
+	checkSubject(t, "SmartCrop", subjectFactory().SmartCrop(50, 50).Image())
+}
+
+func TestFocalFit(t *testing.T) {
+	checkSubject(t, "FocalFit", subjectFactory().FocalFit(50, 50, 250.0/300, 0.5).Image())
+	// the crop stays inside the image for a focal point near the edge
+	im := subjectFactory().FocalFit(50, 50, 1, 0.5).Image()
+	if im.Rect != image.Rect(0, 0, 50, 50) {
+		t.Fatalf("expected a 50x50 image, got %v", im.Rect)
+	}
+	if c := im.NRGBAAt(25, 25); c.R <= c.B {
+		t.Errorf("expected the subject, got %v", c)
+	}
+}
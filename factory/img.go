@@ -15,7 +15,6 @@ import (
 
 	"github.com/FloatTech/gg"
 	"github.com/FloatTech/gg/fio"
-	"github.com/disintegration/imaging"
 )
 
 // Load 加载图片
@@ -141,27 +140,33 @@ func LoadAllTrueFrames(path string, w, h int) ([]*Factory, error) {
 	return ims, nil
 }
 
-// Size 变形
+// Size 变形 使用 Lanczos3 插值
+//
+// w 或 h 为 0 时按另一边保持宽高比(向下取整)，其余不为正数的情况返回原尺寸的副本
 func Size(im image.Image, w, h int) *Factory {
 	sz := im.Bounds().Size()
-	// 修改尺寸
 	switch {
 	case w > 0 && h > 0:
-		return NewFactory(imaging.Resize(im, w, h, imaging.Lanczos))
 	case w == 0 && h > 0:
-		return NewFactory(imaging.Resize(im, h*sz.X/sz.Y, h, imaging.Lanczos))
+		w = h * sz.X / sz.Y
 	case h == 0 && w > 0:
-		return NewFactory(imaging.Resize(im, w, w*sz.Y/sz.X, imaging.Lanczos))
+		h = w * sz.Y / sz.X
 	default:
-		nim := image.NewNRGBA(image.Rect(0, 0, sz.X, sz.Y))
-		draw.Over.Draw(nim, nim.Bounds(), im, im.Bounds().Min)
-		return NewFactory(nim)
+		w, h = 0, 0
 	}
+	return SizeWith(im, w, h, gg.Lanczos3)
+}
+
+// SizeWith 使用插值样式 style 变形
+//
+// w 或 h 不大于 0 时按另一边保持宽高比并四舍五入，两者都不大于 0 时返回原尺寸的副本，参见 gg.Resize
+func SizeWith(im image.Image, w, h int, style gg.ScaleStyle) *Factory {
+	return NewFactory(gg.Resize(im, w, h, style))
 }
 
-// Rotate 旋转
+// Rotate 旋转 逆时针旋转 angle 度，参见 Factory.Rotate
 func Rotate(img image.Image, angle float64, w, h int) *Factory {
-	return NewFactory(imaging.Rotate(Size(img, w, h).im, angle, color.NRGBA{0, 0, 0, 0}))
+	return Size(img, w, h).Rotate(angle, gg.BiLinear)
 }
 
 // MergeW 横向合并图片
@@ -0,0 +1,57 @@
+package factory
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/FloatTech/gg"
+)
+
+func TestSize(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(5, 5, 35, 25))
+	for i := 0; i < len(src.Pix); i += 4 {
+		copy(src.Pix[i:], []uint8{200, 100, 50, 255})
+	}
+	src.SetNRGBA(5, 5, color.NRGBA{90, 60, 30, 77})
+	for _, tc := range []struct {
+		w, h int
+		want image.Point
+	}{
+		{20, 10, image.Pt(20, 10)},
+		// the auto dimension is truncated
+		{20, 0, image.Pt(20, 13)},
+		{0, 7, image.Pt(10, 7)},
+		{0, 19, image.Pt(28, 19)},
+		// anything else copies the image
+		{-1, 10, image.Pt(30, 20)},
+		{9, -3, image.Pt(30, 20)},
+		{0, 0, image.Pt(30, 20)},
+		{-2, -2, image.Pt(30, 20)},
+	} {
+		if got := Size(src, tc.w, tc.h).im.Rect; got != (image.Rectangle{Max: tc.want}) {
+			t.Errorf("Size(%d, %d): expected %v, got %v", tc.w, tc.h, tc.want, got)
+		}
+	}
+	// the copy keeps the pixels as they are
+	out := Size(src, -1, 10).im
+	if got, want := out.NRGBAAt(0, 0), src.NRGBAAt(5, 5); got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got, want := out.NRGBAAt(29, 19), src.NRGBAAt(34, 24); got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	// SizeWith rounds the auto dimension instead, like gg.Resize
+	for _, tc := range []struct {
+		w, h int
+		want image.Point
+	}{
+		{0, 7, image.Pt(11, 7)},
+		{-1, 10, image.Pt(15, 10)},
+		{9, -3, image.Pt(9, 6)},
+	} {
+		if got := SizeWith(src, tc.w, tc.h, gg.Lanczos3).im.Rect; got != (image.Rectangle{Max: tc.want}) {
+			t.Errorf("SizeWith(%d, %d): expected %v, got %v", tc.w, tc.h, tc.want, got)
+		}
+	}
+}
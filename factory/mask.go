@@ -0,0 +1,63 @@
+package factory
+
+import (
+	"github.com/FloatTech/gg"
+	"github.com/FloatTech/gg/internal/pixop"
+)
+
+// Mask 蒙版 只保留 path 在 dc 上绘制的路径内部，边缘抗锯齿
+//
+// path 使用图像的坐标，feather 为羽化宽度(像素)，0 表示不羽化，图像边界处不羽化。在原图像上修改
+func (dst *Factory) Mask(path func(dc *gg.Context), feather float64) *Factory {
+	return dst.mask(path, feather, false)
+}
+
+// MaskOut 反向蒙版 去除 path 在 dc 上绘制的路径内部，参见 Mask
+func (dst *Factory) MaskOut(path func(dc *gg.Context), feather float64) *Factory {
+	return dst.mask(path, feather, true)
+}
+
+// MaskPolygon 多边形蒙版 只保留多边形 points 内部，参见 Mask
+func (dst *Factory) MaskPolygon(points []gg.Point, feather float64) *Factory {
+	return dst.Mask(func(dc *gg.Context) {
+		dc.DrawPolygon(points)
+	}, feather)
+}
+
+// RoundCorners 圆角 将图像的四角裁为半径 r 的圆角，参见 Mask
+func (dst *Factory) RoundCorners(r, feather float64) *Factory {
+	b := dst.im.Bounds()
+	return dst.Mask(func(dc *gg.Context) {
+		dc.DrawRoundedRectangle(float64(b.Min.X), float64(b.Min.Y), float64(b.Dx()), float64(b.Dy()), r)
+	}, feather)
+}
+
+// mask 将透明度乘以 path 填充出的覆盖率，out 为真时乘以其补
+func (dst *Factory) mask(path func(dc *gg.Context), feather float64, out bool) *Factory {
+	b := dst.im.Bounds()
+	if b.Empty() {
+		return dst
+	}
+	dc := gg.NewContext(b.Dx(), b.Dy())
+	dc.Translate(-float64(b.Min.X), -float64(b.Min.Y))
+	path(dc)
+	dc.Fill()
+	if feather > 0 {
+		// 羽化宽度约为两倍标准差
+		dc.ApplyFilter(gg.Blur(feather / 2))
+	}
+	m := dc.AsMask()
+	pixop.Rows(gg.Workers(), b.Dy(), func(y0, y1 int) {
+		for y := y0; y < y1; y++ {
+			row := dst.im.Pix[y*dst.im.Stride : y*dst.im.Stride+4*b.Dx()]
+			cov := m.Pix[y*m.Stride : y*m.Stride+b.Dx()]
+			for x, c := range cov {
+				if out {
+					c = 255 - c
+				}
+				row[4*x+3] = uint8((uint32(row[4*x+3])*uint32(c) + 127) / 255)
+			}
+		}
+	})
+	return dst
+}
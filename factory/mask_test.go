@@ -0,0 +1,83 @@
+package factory
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/FloatTech/gg"
+)
+
+func TestCircle(t *testing.T) {
+	f := NewFactoryBG(60, 40, color.NRGBA{200, 100, 50, 255}).Circle(20)
+	im := f.Image()
+	if im.Rect != image.Rect(0, 0, 40, 40) {
+		t.Fatalf("expected a 40x40 image, got %v", im.Rect)
+	}
+	for _, p := range []image.Point{{20, 20}, {20, 1}, {1, 20}} {
+		if a := im.NRGBAAt(p.X, p.Y).A; a != 255 {
+			t.Errorf("pixel %v: expected an opaque pixel, got alpha %d", p, a)
+		}
+	}
+	for _, p := range []image.Point{{0, 0}, {39, 0}, {0, 39}, {39, 39}, {4, 4}} {
+		if a := im.NRGBAAt(p.X, p.Y).A; a != 0 {
+			t.Errorf("pixel %v: expected a transparent pixel, got alpha %d", p, a)
+		}
+	}
+	// the edge is antialiased and the coverage matches the area
+	var partial int
+	var area float64
+	for i := 3; i < len(im.Pix); i += 4 {
+		if a := im.Pix[i]; a > 0 && a < 255 {
+			partial++
+		}
+		area += float64(im.Pix[i]) / 255
+	}
+	if partial == 0 {
+		t.Error("expected partially covered pixels on the edge")
+	}
+	if want := math.Pi * 20 * 20; math.Abs(area-want)/want > 0.01 {
+		t.Errorf("expected an area of %.1f, got %.1f", want, area)
+	}
+}
+
+func TestMaskOut(t *testing.T) {
+	path := func(dc *gg.Context) {
+		dc.DrawEllipse(30, 20, 18, 11)
+	}
+	for _, feather := range []float64{0, 4} {
+		in := NewFactoryBG(60, 40, color.NRGBA{10, 20, 30, 200}).Mask(path, feather).Image()
+		out := NewFactoryBG(60, 40, color.NRGBA{10, 20, 30, 200}).MaskOut(path, feather).Image()
+		for i := 3; i < len(in.Pix); i += 4 {
+			if sum := int(in.Pix[i]) + int(out.Pix[i]); sum < 199 || sum > 201 {
+				t.Fatalf("feather %v: pixel %d: expected the alphas to add up to 200, got %d", feather, i/4, sum)
+			}
+		}
+		if in.Pix[(20*60+30)*4+3] != 200 || out.Pix[3] != 200 {
+			t.Errorf("feather %v: expected the inside and outside to keep their alpha", feather)
+		}
+	}
+}
+
+func TestClipCircleFix(t *testing.T) {
+	f := NewFactoryBG(100, 100, color.White).Clip(50, 50, 40, 40)
+	im := f.ClipCircleFix(65, 65, 10).Image()
+	if im.Rect != image.Rect(55, 55, 75, 75) {
+		t.Fatalf("expected the bounds (55,55)-(75,75), got %v", im.Rect)
+	}
+	// the circle is centered at the absolute coordinates
+	if a := im.NRGBAAt(65, 65).A; a != 255 {
+		t.Errorf("expected an opaque center, got alpha %d", a)
+	}
+	for _, p := range []image.Point{{55, 55}, {74, 55}, {55, 74}, {74, 74}} {
+		if a := im.NRGBAAt(p.X, p.Y).A; a != 0 {
+			t.Errorf("pixel %v: expected a transparent corner, got alpha %d", p, a)
+		}
+	}
+	for _, p := range []image.Point{{56, 65}, {73, 65}, {65, 56}, {65, 73}} {
+		if a := im.NRGBAAt(p.X, p.Y).A; a == 0 {
+			t.Errorf("pixel %v: expected the circle to reach the edge", p)
+		}
+	}
+}
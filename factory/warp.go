@@ -0,0 +1,60 @@
+package factory
+
+import (
+	"image"
+	"image/draw"
+	"math"
+
+	"github.com/FloatTech/gg"
+)
+
+// Transform 仿射变换 按矩阵 m 变换图像，画布扩展以恰好容纳结果，其余部分透明
+//
+// style 为插值样式，边缘抗锯齿
+func (dst *Factory) Transform(m gg.Matrix, style gg.ScaleStyle) *Factory {
+	b := dst.im.Bounds()
+	w, h := float64(b.Dx()), float64(b.Dy())
+	var corners [4]gg.Point
+	for i, p := range [4]gg.Point{{X: 0, Y: 0}, {X: w, Y: 0}, {X: w, Y: h}, {X: 0, Y: h}} {
+		corners[i].X, corners[i].Y = m.TransformPoint(p.X, p.Y)
+	}
+	x0, y0 := math.Inf(1), math.Inf(1)
+	x1, y1 := math.Inf(-1), math.Inf(-1)
+	for _, p := range corners {
+		x0, y0 = min(x0, p.X), min(y0, p.Y)
+		x1, y1 = max(x1, p.X), max(y1, p.Y)
+	}
+	// 忽略浮点误差，例如旋转 90 度时
+	const eps = 1e-9
+	cw, ch := math.Ceil(x1-x0-eps), math.Ceil(y1-y0-eps)
+	// 结果居中于画布，与像素对齐时不插值
+	dx, dy := x0-(cw-(x1-x0))/2, y0-(ch-(y1-y0))/2
+	if r := math.Round(dx); math.Abs(dx-r) <= eps {
+		dx = r
+	}
+	if r := math.Round(dy); math.Abs(dy-r) <= eps {
+		dy = r
+	}
+	for i := range corners {
+		corners[i].X -= dx
+		corners[i].Y -= dy
+	}
+	return dst.Perspective(int(cw), int(ch), corners, style)
+}
+
+// Rotate 旋转 逆时针旋转 angle 度，画布扩展以恰好容纳结果，其余部分透明，参见 Transform
+func (dst *Factory) Rotate(angle float64, style gg.ScaleStyle) *Factory {
+	return dst.Transform(gg.Rotate(-gg.Radians(angle)), style)
+}
+
+// Perspective 透视变换 在 w x h 的透明画布上绘制图像，使其左上、右上、右下与左下角分别位于 corners 各点
+//
+// 可用于将图像贴到样机屏幕或招牌上。style 为插值样式，边缘抗锯齿，参见 gg.Context.DrawImageQuad
+func (dst *Factory) Perspective(w, h int, corners [4]gg.Point, style gg.ScaleStyle) *Factory {
+	dc := gg.NewContext(max(w, 0), max(h, 0))
+	dc.SetScaleStyle(style)
+	dc.DrawImageQuad(dst.im, corners)
+	im := image.NewNRGBA(image.Rect(0, 0, dc.Width(), dc.Height()))
+	draw.Draw(im, im.Rect, dc.Image(), image.Point{}, draw.Src)
+	return NewFactory(im)
+}
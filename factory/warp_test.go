@@ -0,0 +1,100 @@
+package factory
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/FloatTech/gg"
+)
+
+// patternImage returns a w x h image whose opaque pixels all differ.
+func patternImage(w, h int) *image.NRGBA {
+	im := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			im.SetNRGBA(x, y, color.NRGBA{uint8(40 * x), uint8(40 * y), uint8(7*x + 11*y), 255})
+		}
+	}
+	return im
+}
+
+func TestRotateRightAngles(t *testing.T) {
+	const w, h = 5, 3
+	src := patternImage(w, h)
+	for _, tc := range []struct {
+		angle float64
+		size  image.Point
+		// at returns the source pixel that ends up at (x, y)
+		at func(x, y int) (int, int)
+	}{
+		{90, image.Pt(h, w), func(x, y int) (int, int) { return w - 1 - y, x }},
+		{180, image.Pt(w, h), func(x, y int) (int, int) { return w - 1 - x, h - 1 - y }},
+		{-90, image.Pt(h, w), func(x, y int) (int, int) { return y, h - 1 - x }},
+	} {
+		for _, style := range []gg.ScaleStyle{gg.NearestNeighbor, gg.BiLinear, gg.CatmullRom} {
+			im := NewFactory(src).Rotate(tc.angle, style).Image()
+			if im.Rect != (image.Rectangle{Max: tc.size}) {
+				t.Fatalf("angle %v, style %d: expected a %v image, got %v", tc.angle, style, tc.size, im.Rect)
+			}
+			for y := range tc.size.Y {
+				for x := range tc.size.X {
+					sx, sy := tc.at(x, y)
+					if got, want := im.NRGBAAt(x, y), src.NRGBAAt(sx, sy); got != want {
+						t.Errorf("angle %v, style %d: pixel (%d, %d): expected %v, got %v", tc.angle, style, x, y, want, got)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestRotateCanvas(t *testing.T) {
+	im := NewFactoryBG(40, 20, red).Rotate(45, gg.BiLinear).Image()
+	// (40 + 20) / √2 = 42.4
+	if im.Rect != image.Rect(0, 0, 43, 43) {
+		t.Fatalf("expected a 43x43 image, got %v", im.Rect)
+	}
+	for _, p := range []image.Point{{0, 0}, {42, 0}, {0, 42}, {42, 42}, {5, 5}} {
+		if a := im.NRGBAAt(p.X, p.Y).A; a != 0 {
+			t.Errorf("pixel %v: expected a transparent pixel, got alpha %d", p, a)
+		}
+	}
+	for _, p := range []image.Point{{21, 21}, {10, 21}, {32, 21}} {
+		if got := im.NRGBAAt(p.X, p.Y); got != red {
+			t.Errorf("pixel %v: expected %v, got %v", p, red, got)
+		}
+	}
+}
+
+func TestRotate(t *testing.T) {
+	src := patternImage(5, 3)
+	want := NewFactory(src).Rotate(90, gg.BiLinear).Image()
+	if got := Rotate(src, 90, 0, 0).Image(); got.Rect != want.Rect || string(got.Pix) != string(want.Pix) {
+		t.Errorf("expected the image rotated as it is")
+	}
+	// the image is resized before it is rotated
+	if got := Rotate(src, 90, 10, 6).Image().Rect; got != image.Rect(0, 0, 6, 10) {
+		t.Errorf("expected a 6x10 image, got %v", got)
+	}
+}
+
+func TestPerspective(t *testing.T) {
+	src := patternImage(4, 4)
+	corners := [4]gg.Point{{X: 2, Y: 1}, {X: 6, Y: 1}, {X: 6, Y: 5}, {X: 2, Y: 5}}
+	im := NewFactory(src).Perspective(8, 7, corners, gg.NearestNeighbor).Image()
+	if im.Rect != image.Rect(0, 0, 8, 7) {
+		t.Fatalf("expected an 8x7 image, got %v", im.Rect)
+	}
+	for y := range 7 {
+		for x := range 8 {
+			want := color.NRGBA{}
+			if x >= 2 && x < 6 && y >= 1 && y < 5 {
+				want = src.NRGBAAt(x-2, y-1)
+			}
+			if got := im.NRGBAAt(x, y); got != want {
+				t.Errorf("pixel (%d, %d): expected %v, got %v", x, y, want, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,594 @@
+package gg
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"golang.org/x/image/draw"
+
+	"github.com/FloatTech/gg/internal/pixop"
+)
+
+// Filter is an image filter, like an SVG filter primitive or a CSS filter
+// function. Filters are combined with Chain and Merge into filter graphs.
+//
+// Filter 为图像滤镜，类似 SVG 滤镜原语或 CSS 滤镜函数。
+// 可以用 Chain 和 Merge 将滤镜组合成滤镜图。
+type Filter interface {
+	// Filter returns the result of filtering src, with the bounds of src.
+	// It must not modify src.
+	//
+	// Filter 返回对 src 滤镜处理的结果，其边界与 src 相同。不得修改 src。
+	Filter(src *image.NRGBA) *image.NRGBA
+}
+
+// FilterFunc is an adapter to use an ordinary function as a Filter.
+//
+// FilterFunc 是将普通函数用作 Filter 的适配器。
+type FilterFunc func(src *image.NRGBA) *image.NRGBA
+
+// Filter calls f(src).
+//
+// Filter 调用 f(src)。
+func (f FilterFunc) Filter(src *image.NRGBA) *image.NRGBA {
+	return f(src)
+}
+
+// ApplyFilter applies the filter f to the image of the context. Only the
+// pixels inside the current clip mask are changed.
+//
+// ApplyFilter 对上下文的图像应用滤镜 f。只会改变当前裁剪蒙版内的像素。
+func (dc *Context) ApplyFilter(f Filter) {
+	dc.ApplyFilterRect(f, image.Rect(0, 0, dc.width, dc.height))
+}
+
+// ApplyFilterRect applies the filter f to the rectangle r of the image of
+// the context, as if it were an image of its own. Only the pixels inside
+// the current clip mask are changed. Filters work on 8-bit colors: on
+// 16-bit and float contexts, the pixels that f leaves unchanged keep their
+// full precision, but the pixels it changes are rounded to 8 bits.
+//
+// ApplyFilterRect 对上下文图像中的矩形 r 应用滤镜 f，如同它是一张独立的图像。
+// 只会改变当前裁剪蒙版内的像素。滤镜处理 8 位颜色：在 16 位与浮点上下文中，
+// f 未改变的像素保持完整精度，但被改变的像素会舍入到 8 位。
+func (dc *Context) ApplyFilterRect(f Filter, r image.Rectangle) {
+	r = r.Intersect(image.Rect(0, 0, dc.width, dc.height))
+	if r.Empty() {
+		return
+	}
+	src := image.NewNRGBA(r)
+	draw.Draw(src, r, dc.im, r.Min, draw.Src)
+	res := f.Filter(src)
+	if im, ok := dc.im.(draw.RGBA64Image); ok && isDeep(dc.im) {
+		dc.replaceRectDeep(im, r, src, res)
+		return
+	}
+	dc.replaceRect(r, res)
+}
+
+// isDeep reports whether im stores more than 8 bits per component.
+func isDeep(im image.Image) bool {
+	switch im.(type) {
+	case *image.RGBA64, *image.NRGBA64, *image.Gray16, *RGBAF32:
+		return true
+	}
+	return false
+}
+
+// replaceRect replaces the rectangle r of the image with im, keeping the
+// current pixels outside the clip mask.
+func (dc *Context) replaceRect(r image.Rectangle, im image.Image) {
+	if dc.mask == nil {
+		draw.Draw(dc.im, r, im, r.Min, draw.Src)
+		return
+	}
+	res := image.NewRGBA(r)
+	draw.Draw(res, r, im, r.Min, draw.Src)
+	old := image.NewRGBA(r)
+	draw.Draw(old, r, dc.im, r.Min, draw.Src)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			k := uint32(dc.mask.AlphaAt(x, y).A)
+			if k == 255 {
+				continue
+			}
+			i := res.PixOffset(x, y)
+			for j := i; j < i+4; j++ {
+				res.Pix[j] = uint8((uint32(res.Pix[j])*k + uint32(old.Pix[j])*(255-k) + 127) / 255)
+			}
+		}
+	}
+	draw.Draw(dc.im, r, res, r.Min, draw.Src)
+}
+
+// replaceRectDeep is replaceRect for images deeper than 8 bits. It only
+// writes the pixels of res that differ from the 8-bit copy src of the
+// image, so that the others keep their precision, and blends with the clip
+// mask in 16 bits.
+func (dc *Context) replaceRectDeep(im draw.RGBA64Image, r image.Rectangle, src, res *image.NRGBA) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			c := res.NRGBAAt(x, y)
+			if c == src.NRGBAAt(x, y) {
+				continue
+			}
+			nr, ng, nb, na := c.RGBA()
+			if dc.mask != nil {
+				k := uint32(dc.mask.AlphaAt(x, y).A)
+				if k == 0 {
+					continue
+				}
+				o := im.RGBA64At(x, y)
+				mix := func(n uint32, o uint16) uint32 {
+					return (n*k + uint32(o)*(255-k) + 127) / 255
+				}
+				nr, ng, nb, na = mix(nr, o.R), mix(ng, o.G), mix(nb, o.B), mix(na, o.A)
+			}
+			im.SetRGBA64(x, y, color.RGBA64{uint16(nr), uint16(ng), uint16(nb), uint16(na)})
+		}
+	}
+}
+
+// Chain returns a filter that applies filters one after another, like a
+// CSS filter list.
+//
+// Chain 返回依次应用各滤镜的滤镜，类似 CSS 的滤镜列表。
+func Chain(filters ...Filter) Filter {
+	return FilterFunc(func(src *image.NRGBA) *image.NRGBA {
+		for _, f := range filters {
+			src = f.Filter(src)
+		}
+		return src
+	})
+}
+
+// Merge returns a filter that applies every filter to the same source and
+// composites the results over one another, the first one at the bottom,
+// like SVG's feMerge. A nil filter stands for the source itself.
+//
+// Merge 返回对同一源图像分别应用各滤镜，并将结果依次叠加的滤镜，第一个结果位于最底层，
+// 类似 SVG 的 feMerge。nil 滤镜代表源图像本身。
+func Merge(filters ...Filter) Filter {
+	return FilterFunc(func(src *image.NRGBA) *image.NRGBA {
+		dst := image.NewRGBA(src.Rect)
+		for _, f := range filters {
+			im := src
+			if f != nil {
+				im = f.Filter(src)
+			}
+			draw.Draw(dst, dst.Rect, im, im.Rect.Min, draw.Over)
+		}
+		return ImageToNRGBA(dst)
+	})
+}
+
+// Offset returns a filter that moves the image by (dx, dy) pixels.
+//
+// Offset 返回将图像平移 (dx, dy) 像素的滤镜。
+func Offset(dx, dy int) Filter {
+	return FilterFunc(func(src *image.NRGBA) *image.NRGBA {
+		dst := image.NewNRGBA(src.Rect)
+		draw.Draw(dst, dst.Rect.Add(image.Pt(dx, dy)), src, src.Rect.Min, draw.Src)
+		return dst
+	})
+}
+
+// pixelFilter returns a filter that applies op to every pixel of a copy of
+// the image.
+func pixelFilter(op pixop.Op) Filter {
+	return FilterFunc(func(src *image.NRGBA) *image.NRGBA {
+		dst := image.NewNRGBA(src.Rect)
+		w := 4 * src.Rect.Dx()
+		for y := range src.Rect.Dy() {
+			copy(dst.Pix[y*dst.Stride:y*dst.Stride+w], src.Pix[y*src.Stride:y*src.Stride+w])
+		}
+		pixop.Apply(Workers(), dst.Pix, dst.Stride, src.Rect.Dx(), src.Rect.Dy(), op)
+		return dst
+	})
+}
+
+// ColorMatrix returns a filter that transforms the straight color of every
+// pixel by the 4x5 matrix m in row-major order, like SVG's feColorMatrix:
+// the new red is m[0]*r + m[1]*g + m[2]*b + m[3]*a + m[4], with all
+// components in [0, 1], and so on for green, blue and alpha.
+//
+// ColorMatrix 返回以行优先的 4x5 矩阵 m 变换每个像素非预乘颜色的滤镜，与 SVG 的 feColorMatrix 相同：
+// 新的红色分量为 m[0]*r + m[1]*g + m[2]*b + m[3]*a + m[4]，所有分量均在 [0, 1] 内，
+// 绿、蓝与 alpha 分量依此类推。
+func ColorMatrix(m [20]float64) Filter {
+	return pixelFilter(pixop.Matrix(m))
+}
+
+// rgbMatrix returns the color matrix that transforms the color components
+// by the 3x3 matrix m and leaves alpha unchanged.
+func rgbMatrix(m [9]float64) Filter {
+	return ColorMatrix([20]float64{
+		m[0], m[1], m[2], 0, 0,
+		m[3], m[4], m[5], 0, 0,
+		m[6], m[7], m[8], 0, 0,
+		0, 0, 0, 1, 0,
+	})
+}
+
+// HueRotate returns a filter that rotates the hue of the image by deg
+// degrees.
+//
+// HueRotate 返回将图像色相旋转 deg 度的滤镜。
+func HueRotate(deg float64) Filter {
+	sin, cos := math.Sincos(Radians(deg))
+	return rgbMatrix([9]float64{
+		0.213 + cos*0.787 - sin*0.213, 0.715 - cos*0.715 - sin*0.715, 0.072 - cos*0.072 + sin*0.928,
+		0.213 - cos*0.213 + sin*0.143, 0.715 + cos*0.285 + sin*0.140, 0.072 - cos*0.072 - sin*0.283,
+		0.213 - cos*0.213 - sin*0.787, 0.715 - cos*0.715 + sin*0.715, 0.072 + cos*0.928 + sin*0.072,
+	})
+}
+
+// Saturate returns a filter that scales the saturation of the image by s,
+// so 0 gives grays and values above 1 oversaturate.
+//
+// Saturate 返回将图像饱和度缩放 s 倍的滤镜，0 得到灰度，大于 1 则过饱和。
+func Saturate(s float64) Filter {
+	return rgbMatrix([9]float64{
+		0.213 + 0.787*s, 0.715 - 0.715*s, 0.072 - 0.072*s,
+		0.213 - 0.213*s, 0.715 + 0.285*s, 0.072 - 0.072*s,
+		0.213 - 0.213*s, 0.715 - 0.715*s, 0.072 + 0.928*s,
+	})
+}
+
+// Grayscale returns a filter that converts the image to grayscale by
+// amount in [0, 1]. Like CSS, it weights the colors by Rec. 709, so its
+// pixels differ slightly from those of factory's Grayscale, which keeps
+// the Rec. 601 weights.
+//
+// Grayscale 返回按 amount（[0, 1]）将图像转为灰度的滤镜。与 CSS 相同，它按 Rec. 709 加权颜色，
+// 因此其像素与沿用 Rec. 601 权重的 factory 中的 Grayscale 略有不同。
+func Grayscale(amount float64) Filter {
+	s := 1 - max(0, min(1, amount))
+	return rgbMatrix([9]float64{
+		0.2126 + 0.7874*s, 0.7152 - 0.7152*s, 0.0722 - 0.0722*s,
+		0.2126 - 0.2126*s, 0.7152 + 0.2848*s, 0.0722 - 0.0722*s,
+		0.2126 - 0.2126*s, 0.7152 - 0.7152*s, 0.0722 + 0.9278*s,
+	})
+}
+
+// Sepia returns a filter that converts the image to sepia by amount in
+// [0, 1].
+//
+// Sepia 返回按 amount（[0, 1]）将图像转为棕褐色调的滤镜。
+func Sepia(amount float64) Filter {
+	return ColorMatrix(pixop.SepiaMatrix(amount))
+}
+
+// Transfer maps a color component in [0, 1] to a new value, see
+// ComponentTransfer.
+//
+// Transfer 将 [0, 1] 内的颜色分量映射为新值，参见 ComponentTransfer。
+type Transfer func(v float64) float64
+
+// TransferTable returns the transfer function that interpolates linearly
+// between values spread evenly over [0, 1].
+//
+// TransferTable 返回在均匀分布于 [0, 1] 的 values 之间线性插值的传递函数。
+func TransferTable(values ...float64) Transfer {
+	return func(v float64) float64 {
+		n := len(values) - 1
+		if n < 1 {
+			return v
+		}
+		k := min(int(v*float64(n)), n-1)
+		return mix(values[k], values[k+1], v*float64(n)-float64(k))
+	}
+}
+
+// TransferDiscrete returns the step transfer function that maps n equal
+// parts of [0, 1] to the n values.
+//
+// TransferDiscrete 返回阶梯传递函数，将 [0, 1] 等分为 n 段并分别映射为 n 个值。
+func TransferDiscrete(values ...float64) Transfer {
+	return func(v float64) float64 {
+		n := len(values)
+		if n == 0 {
+			return v
+		}
+		return values[min(int(v*float64(n)), n-1)]
+	}
+}
+
+// TransferLinear returns the transfer function slope*v + intercept.
+//
+// TransferLinear 返回传递函数 slope*v + intercept。
+func TransferLinear(slope, intercept float64) Transfer {
+	return func(v float64) float64 {
+		return slope*v + intercept
+	}
+}
+
+// TransferGamma returns the transfer function
+// amplitude*v^exponent + offset.
+//
+// TransferGamma 返回传递函数 amplitude*v^exponent + offset。
+func TransferGamma(amplitude, exponent, offset float64) Transfer {
+	return func(v float64) float64 {
+		return amplitude*math.Pow(v, exponent) + offset
+	}
+}
+
+// ComponentTransfer returns a filter that maps each straight component of
+// every pixel through its transfer function, like SVG's
+// feComponentTransfer. A nil function leaves the component unchanged.
+//
+// ComponentTransfer 返回将每个像素的各非预乘分量分别通过其传递函数映射的滤镜，
+// 与 SVG 的 feComponentTransfer 相同。nil 函数保持该分量不变。
+func ComponentTransfer(r, g, b, a Transfer) Filter {
+	l := pixop.Identity()
+	for c, f := range [4]Transfer{r, g, b, a} {
+		l.Transfer(c, f)
+	}
+	return pixelFilter(l.Op())
+}
+
+// Invert returns a filter that inverts the colors of the image by amount
+// in [0, 1].
+//
+// Invert 返回按 amount（[0, 1]）反转图像颜色的滤镜。
+func Invert(amount float64) Filter {
+	t := TransferTable(amount, 1-amount)
+	return ComponentTransfer(t, t, t, nil)
+}
+
+// Gamma returns a filter that applies the gamma g to the colors of the
+// image, so values above 1 brighten it.
+//
+// Gamma 返回对图像颜色应用伽马值 g 的滤镜，大于 1 时图像变亮。
+func Gamma(g float64) Filter {
+	t := TransferGamma(1, 1/g, 0)
+	return ComponentTransfer(t, t, t, nil)
+}
+
+// Threshold returns a filter that turns the pixels whose luma is at least
+// level white and the others black. The luma has the Rec. 709 weights of
+// Grayscale.
+//
+// Threshold 返回将亮度不低于 level 的像素变为白色、其余像素变为黑色的滤镜。亮度使用与 Grayscale 相同的 Rec. 709 权重。
+func Threshold(level uint8) Filter {
+	return pixelFilter(pixop.Threshold(level, pixop.Rec709))
+}
+
+// Posterize returns a filter that reduces every color component to the
+// given number of evenly spaced levels.
+//
+// Posterize 返回将每个颜色分量减少为给定数量的均匀分布色阶的滤镜。
+func Posterize(levels int) Filter {
+	return pixelFilter(pixop.Posterize(levels).Op())
+}
+
+// Duotone returns a filter that maps the luma of the image to the gradient
+// from the color dark to light. The luma has the Rec. 709 weights of
+// Grayscale.
+//
+// Duotone 返回将图像亮度映射到从 dark 到 light 的渐变色的滤镜。亮度使用与 Grayscale 相同的 Rec. 709 权重。
+func Duotone(dark, light color.Color) Filter {
+	return pixelFilter(pixop.Duotone(pixop.RGB8(dark), pixop.RGB8(light), pixop.Rec709))
+}
+
+// SwapChannels returns a filter that sets the red, green, blue and alpha
+// of every pixel to its channels r, g, b and a.
+//
+// SwapChannels 返回将每个像素的红、绿、蓝与 alpha 分量分别设为其通道 r、g、b 与 a 的滤镜。
+func SwapChannels(r, g, b, a Channel) Filter {
+	return pixelFilter(pixop.Swap(int(r), int(g), int(b), int(a)))
+}
+
+// Levels returns a filter that maps the color range [inBlack, inWhite] to
+// [outBlack, outWhite], with the gamma correction gamma in between, like
+// the levels dialog of image editors.
+//
+// Levels 返回将颜色范围 [inBlack, inWhite] 映射到 [outBlack, outWhite] 的滤镜，
+// 中间应用伽马校正 gamma，与图像编辑器中的色阶对话框相同。
+func Levels(inBlack, inWhite uint8, gamma float64, outBlack, outWhite uint8) Filter {
+	return pixelFilter(pixop.Levels(inBlack, inWhite, gamma, outBlack, outWhite).Op())
+}
+
+// Curves returns a filter that maps the red, green and blue components
+// through the tone curves r, g and b. A nil curve leaves its component
+// unchanged.
+//
+// Curves 返回将红、绿、蓝分量分别通过色调曲线 r、g、b 映射的滤镜。nil 曲线保持该分量不变。
+func Curves(r, g, b *[256]uint8) Filter {
+	return pixelFilter(pixop.Curves(r, g, b).Op())
+}
+
+// premultipliedPix returns the premultiplied components of src in [0, 1].
+func premultipliedPix(src *image.NRGBA) []float64 {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	p := make([]float64, 4*w*h)
+	for y := range h {
+		s := src.Pix[y*src.Stride : y*src.Stride+4*w]
+		for i := 0; i < 4*w; i += 4 {
+			a := float64(s[i+3]) / 255
+			k := 4*y*w + i
+			p[k], p[k+1], p[k+2], p[k+3] = float64(s[i])/255*a, float64(s[i+1])/255*a, float64(s[i+2])/255*a, a
+		}
+	}
+	return p
+}
+
+// fromPremultipliedPix converts the premultiplied components p back to an
+// image with the bounds r.
+func fromPremultipliedPix(p []float64, r image.Rectangle) *image.NRGBA {
+	dst := image.NewNRGBA(r)
+	w := r.Dx()
+	for y := range r.Dy() {
+		d := dst.Pix[y*dst.Stride : y*dst.Stride+4*w]
+		for i := 0; i < 4*w; i += 4 {
+			k := 4*y*w + i
+			a := max(0, min(1, p[k+3]))
+			if a == 0 {
+				continue
+			}
+			for j := range 3 {
+				d[i+j] = uint8(max(0, min(1, p[k+j]/a))*255 + 0.5)
+			}
+			d[i+3] = uint8(a*255 + 0.5)
+		}
+	}
+	return dst
+}
+
+// Convolve returns a filter that convolves the premultiplied image with
+// the kernel of the given width, centered on every pixel, like SVG's
+// feConvolveMatrix. The result is divided by the sum of the kernel unless
+// it is 0. Pixels beyond the edges repeat the edge pixels.
+//
+// Convolve 返回以给定宽度的卷积核对预乘图像进行卷积的滤镜，卷积核中心对准每个像素，
+// 与 SVG 的 feConvolveMatrix 相同。结果会除以卷积核之和（和为 0 时除外）。
+// 边缘之外的像素重复边缘像素。
+func Convolve(kernel []float64, width int) Filter {
+	return FilterFunc(func(src *image.NRGBA) *image.NRGBA {
+		if width <= 0 || len(kernel)%width != 0 {
+			return src
+		}
+		height := len(kernel) / width
+		div := 0.0
+		for _, k := range kernel {
+			div += k
+		}
+		if div == 0 {
+			div = 1
+		}
+		w, h := src.Rect.Dx(), src.Rect.Dy()
+		p := premultipliedPix(src)
+		out := make([]float64, len(p))
+		for y := range h {
+			for x := range w {
+				var sum [4]float64
+				for ky := range height {
+					sy := max(0, min(h-1, y+ky-height/2))
+					for kx := range width {
+						k := kernel[ky*width+kx]
+						if k == 0 {
+							continue
+						}
+						sx := max(0, min(w-1, x+kx-width/2))
+						i := 4 * (sy*w + sx)
+						for j := range sum {
+							sum[j] += k * p[i+j]
+						}
+					}
+				}
+				i := 4 * (y*w + x)
+				a := max(0, min(1, sum[3]/div))
+				for j := range 3 {
+					out[i+j] = max(0, min(a, sum[j]/div))
+				}
+				out[i+3] = a
+			}
+		}
+		return fromPremultipliedPix(out, src.Rect)
+	})
+}
+
+// Dilate returns a filter that fattens the image by taking the maximum of
+// every premultiplied component over the (2rx+1)x(2ry+1) neighbourhood of
+// every pixel, like SVG's feMorphology.
+//
+// Dilate 返回使图像变粗的滤镜，对每个像素取其 (2rx+1)x(2ry+1) 邻域内各预乘分量的最大值，
+// 与 SVG 的 feMorphology 相同。
+func Dilate(rx, ry int) Filter {
+	return morphology(rx, ry, math.Max)
+}
+
+// Erode returns a filter that thins the image by taking the minimum of
+// every premultiplied component over the (2rx+1)x(2ry+1) neighbourhood of
+// every pixel.
+//
+// Erode 返回使图像变细的滤镜，对每个像素取其 (2rx+1)x(2ry+1) 邻域内各预乘分量的最小值。
+func Erode(rx, ry int) Filter {
+	return morphology(rx, ry, math.Min)
+}
+
+// morphology combines the neighbourhoods of the pixels with op, first
+// along the rows and then along the columns.
+func morphology(rx, ry int, op func(a, b float64) float64) Filter {
+	return FilterFunc(func(src *image.NRGBA) *image.NRGBA {
+		w, h := src.Rect.Dx(), src.Rect.Dy()
+		p := premultipliedPix(src)
+		pass := func(p []float64, r, n, m, step, stride int) []float64 {
+			out := make([]float64, len(p))
+			for j := range m {
+				for i := range n {
+					k := 4 * (j*stride + i*step)
+					copy(out[k:k+4], p[k:k+4])
+					for d := max(0, i-r); d <= min(n-1, i+r); d++ {
+						s := 4 * (j*stride + d*step)
+						for c := range 4 {
+							out[k+c] = op(out[k+c], p[s+c])
+						}
+					}
+				}
+			}
+			return out
+		}
+		p = pass(p, max(0, rx), w, h, 1, w)
+		p = pass(p, max(0, ry), h, w, w, 1)
+		return fromPremultipliedPix(p, src.Rect)
+	})
+}
+
+// Channel selects a color component.
+//
+// Channel 选择一个颜色分量。
+type Channel int
+
+// Color channels.
+//
+// 颜色通道。
+const (
+	ChannelR Channel = iota // Red. 红色。
+	ChannelG                // Green. 绿色。
+	ChannelB                // Blue. 蓝色。
+	ChannelA                // Alpha. 透明度。
+)
+
+// valid returns c, or ChannelA if c is not one of the channels, as SVG
+// does for unknown channel selectors.
+func (c Channel) valid() Channel {
+	if c < ChannelR || c > ChannelA {
+		return ChannelA
+	}
+	return c
+}
+
+// DisplacementMap returns a filter that moves the pixels of the image by
+// the straight components of the map m, like SVG's feDisplacementMap: the
+// pixel at (x, y) is taken from
+// (x + scale*(X(x, y) - 0.5), y + scale*(Y(x, y) - 0.5)), where X and Y
+// are the xChannel and yChannel of m in [0, 1]. m is aligned with the top
+// left corner of the filtered image. Invalid channels select alpha.
+//
+// DisplacementMap 返回按位移图 m 的非预乘分量移动图像像素的滤镜，与 SVG 的 feDisplacementMap 相同：
+// 位于 (x, y) 的像素取自 (x + scale*(X(x, y) - 0.5), y + scale*(Y(x, y) - 0.5))，
+// 其中 X 与 Y 为 m 的 xChannel 与 yChannel 分量，取值在 [0, 1] 内。m 与被处理图像的左上角对齐。
+// 无效的通道按 ChannelA 处理。
+func DisplacementMap(m image.Image, scale float64, xChannel, yChannel Channel) Filter {
+	xChannel, yChannel = xChannel.valid(), yChannel.valid()
+	return FilterFunc(func(src *image.NRGBA) *image.NRGBA {
+		dst := image.NewNRGBA(src.Rect)
+		mb := m.Bounds()
+		for y := range src.Rect.Dy() {
+			for x := range src.Rect.Dx() {
+				c := color.NRGBAModel.Convert(m.At(mb.Min.X+x, mb.Min.Y+y)).(color.NRGBA)
+				v := [4]uint8{c.R, c.G, c.B, c.A}
+				dx := scale * (float64(v[xChannel])/255 - 0.5)
+				dy := scale * (float64(v[yChannel])/255 - 0.5)
+				sx := src.Rect.Min.X + x + int(math.Round(dx))
+				sy := src.Rect.Min.Y + y + int(math.Round(dy))
+				if image.Pt(sx, sy).In(src.Rect) {
+					dst.SetNRGBA(src.Rect.Min.X+x, src.Rect.Min.Y+y, src.NRGBAAt(sx, sy))
+				}
+			}
+		}
+		return dst
+	})
+}
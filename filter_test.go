@@ -0,0 +1,164 @@
+package gg
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"golang.org/x/image/draw"
+)
+
+var invertCurve = func() (c [256]uint8) {
+	for i := range c {
+		c[i] = 255 - uint8(i)
+	}
+	return
+}()
+
+func TestColorFilters(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 3, 1))
+	src.SetNRGBA(0, 0, color.NRGBA{255, 0, 0, 255})
+	src.SetNRGBA(1, 0, color.NRGBA{20, 140, 220, 128})
+	src.SetNRGBA(2, 0, color.NRGBA{255, 255, 255, 255})
+	for _, tc := range []struct {
+		name string
+		f    Filter
+		want [3]color.NRGBA
+	}{
+		{"hue-rotate 360", HueRotate(360), [3]color.NRGBA{{255, 0, 0, 255}, {20, 140, 220, 128}, {255, 255, 255, 255}}},
+		{"invert", Invert(1), [3]color.NRGBA{{0, 255, 255, 255}, {235, 115, 35, 128}, {0, 0, 0, 255}}},
+		{"grayscale", Grayscale(1), [3]color.NRGBA{{54, 54, 54, 255}, {121, 121, 121, 128}, {255, 255, 255, 255}}},
+		{"discrete", ComponentTransfer(TransferDiscrete(0, 1), nil, nil, TransferLinear(0, 1)),
+			[3]color.NRGBA{{255, 0, 0, 255}, {0, 140, 220, 255}, {255, 255, 255, 255}}},
+		{"threshold", Threshold(100), [3]color.NRGBA{{0, 0, 0, 255}, {255, 255, 255, 128}, {255, 255, 255, 255}}},
+		{"posterize", Posterize(2), [3]color.NRGBA{{255, 0, 0, 255}, {0, 255, 255, 128}, {255, 255, 255, 255}}},
+		{"duotone", Duotone(color.Black, color.NRGBA{255, 0, 0, 255}),
+			[3]color.NRGBA{{54, 0, 0, 255}, {120, 0, 0, 128}, {255, 0, 0, 255}}},
+		{"swap", SwapChannels(ChannelB, ChannelG, ChannelR, ChannelA),
+			[3]color.NRGBA{{0, 0, 255, 255}, {220, 140, 20, 128}, {255, 255, 255, 255}}},
+		{"levels", Levels(0, 255, 1, 0, 128), [3]color.NRGBA{{128, 0, 0, 255}, {10, 70, 110, 128}, {128, 128, 128, 255}}},
+		{"curves", Curves(&invertCurve, nil, nil), [3]color.NRGBA{{0, 0, 0, 255}, {235, 140, 220, 128}, {0, 255, 255, 255}}},
+		{"chain", Chain(Invert(1), Invert(1)), [3]color.NRGBA{{255, 0, 0, 255}, {20, 140, 220, 128}, {255, 255, 255, 255}}},
+	} {
+		out := tc.f.Filter(src)
+		for x, want := range tc.want {
+			if got := out.NRGBAAt(x, 0); !sameColor(got, want) {
+				t.Errorf("%s: pixel %d: expected %v, got %v", tc.name, x, want, got)
+			}
+		}
+	}
+}
+
+func TestConvolveMorphology(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 5, 5))
+	src.SetNRGBA(2, 2, color.NRGBA{255, 255, 255, 255})
+	// no dark fringe around a blurred pixel on transparency
+	blur := Convolve([]float64{1, 1, 1, 1, 1, 1, 1, 1, 1}, 3).Filter(src)
+	if got, want := blur.NRGBAAt(1, 1), (color.NRGBA{255, 255, 255, 28}); got != want {
+		t.Errorf("blur: expected %v, got %v", want, got)
+	}
+	dilated := Dilate(1, 1).Filter(src)
+	for y := range 5 {
+		for x := range 5 {
+			in := x >= 1 && x <= 3 && y >= 1 && y <= 3
+			if got := dilated.NRGBAAt(x, y).A == 255; got != in {
+				t.Fatalf("dilate: (%d, %d) is opaque: %v", x, y, got)
+			}
+		}
+	}
+	eroded := Erode(1, 1).Filter(dilated)
+	if eroded.NRGBAAt(2, 2).A != 255 || eroded.NRGBAAt(1, 2).A != 0 {
+		t.Fatalf("erode: expected a single pixel, got %v", eroded.Pix)
+	}
+}
+
+func TestDisplacementMap(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	src.SetNRGBA(5, 5, color.NRGBA{255, 0, 0, 255})
+	// X = 1 and Y = 0 move every pixel by (-2, 2) with a scale of 4
+	m := image.NewUniform(color.NRGBA{255, 0, 0, 255})
+	out := DisplacementMap(m, 4, ChannelR, ChannelG).Filter(src)
+	if out.NRGBAAt(3, 7).A != 255 || out.NRGBAAt(5, 5).A != 0 {
+		t.Fatalf("expected the pixel to move to (3, 7)")
+	}
+	// invalid channels select the alpha of 1, moving every pixel by (-2, -2)
+	out = DisplacementMap(m, 4, Channel(-1), Channel(7)).Filter(src)
+	if out.NRGBAAt(3, 3).A != 255 || out.NRGBAAt(5, 5).A != 0 {
+		t.Fatalf("expected the pixel to move to (3, 3)")
+	}
+}
+
+func TestApplyFilter(t *testing.T) {
+	dc := NewContext(100, 100)
+	dc.SetRGB(1, 0, 0)
+	dc.Clear()
+	dc.ApplyFilterRect(Invert(1), image.Rect(10, 10, 30, 30))
+	dc.DrawCircle(70, 70, 20)
+	dc.Clip()
+	dc.ApplyFilter(Grayscale(1))
+	im := dc.im.(*image.RGBA)
+	for _, tc := range []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{5, 5, color.RGBA{255, 0, 0, 255}},
+		{20, 20, color.RGBA{0, 255, 255, 255}},
+		{70, 70, color.RGBA{54, 54, 54, 255}},
+		{95, 95, color.RGBA{255, 0, 0, 255}},
+	} {
+		if got := im.RGBAAt(tc.x, tc.y); !sameColor(got, tc.want) {
+			t.Errorf("(%d, %d): expected %v, got %v", tc.x, tc.y, tc.want, got)
+		}
+	}
+}
+
+func TestApplyFilterDeep(t *testing.T) {
+	// a 16-bit gradient whose values are not multiples of 0x101
+	for _, dc := range []*Context{NewContextRGBA64(64, 1), NewContextRGBAF32(64, 1, true)} {
+		for x := range 64 {
+			v := uint16(x*997 + 13)
+			dc.im.(draw.RGBA64Image).SetRGBA64(x, 0, color.RGBA64{v, v / 2, v / 3, 0xffff})
+		}
+		want := image.NewRGBA64(image.Rect(0, 0, 64, 1))
+		draw.Draw(want, want.Rect, dc.im, image.Point{}, draw.Src)
+		// a filter that changes nothing keeps every pixel as it is
+		dc.ApplyFilter(FilterFunc(func(src *image.NRGBA) *image.NRGBA { return src }))
+		dc.ApplyFilter(Brightness(0))
+		for x := range 64 {
+			if got := dc.im.(draw.RGBA64Image).RGBA64At(x, 0); got != want.RGBA64At(x, 0) {
+				t.Fatalf("%T: pixel %d: expected %v, got %v", dc.im, x, want.RGBA64At(x, 0), got)
+			}
+		}
+		// and so do the pixels outside of the clip mask
+		dc.DrawRectangle(0, 0, 32, 1)
+		dc.Clip()
+		dc.ApplyFilter(Invert(1))
+		dc.ResetClip()
+		for x := 32; x < 64; x++ {
+			if got := dc.im.(draw.RGBA64Image).RGBA64At(x, 0); got != want.RGBA64At(x, 0) {
+				t.Fatalf("%T: pixel %d: expected %v, got %v", dc.im, x, want.RGBA64At(x, 0), got)
+			}
+		}
+		if got := dc.im.(draw.RGBA64Image).RGBA64At(5, 0); sameColor(got, want.RGBA64At(5, 0)) {
+			t.Errorf("%T: expected the filter to invert the clipped pixels", dc.im)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	src.SetNRGBA(2, 2, color.NRGBA{255, 255, 255, 255})
+	shadow := Chain(ColorMatrix([20]float64{
+		0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0,
+		0, 0, 0, 1, 0,
+	}), Offset(1, 1))
+	out := Merge(shadow, nil).Filter(src)
+	if got := out.NRGBAAt(2, 2); got != (color.NRGBA{255, 255, 255, 255}) {
+		t.Errorf("expected the source on top, got %v", got)
+	}
+	if got := out.NRGBAAt(3, 3); got != (color.NRGBA{0, 0, 0, 255}) {
+		t.Errorf("expected the shadow below, got %v", got)
+	}
+}
@@ -9,6 +9,7 @@ import (
 type stop struct {
 	pos   float64
 	color color.Color
+	rgba  color.RGBA64 // premultiplied components of color
 }
 
 type stops []stop
@@ -28,55 +29,214 @@ func (s stops) Swap(i, j int) {
 	s[i], s[j] = s[j], s[i]
 }
 
+// Spread defines how a gradient is extended outside of its color stops,
+// like the spreadMethod attribute of SVG and the extend mode of Cairo.
+//
+// Spread 定义渐变在色标范围之外的延伸方式，
+// 与 SVG 的 spreadMethod 属性和 Cairo 的 extend 模式相同。
+type Spread int
+
+// Gradient spread modes.
+//
+// 渐变延伸模式。
+const (
+	SpreadPad     Spread = iota // Use the color of the nearest end stop. 使用最近一端色标的颜色。
+	SpreadRepeat                // Repeat the gradient. 重复渐变。
+	SpreadReflect               // Repeat the gradient, mirroring every other period. 镜像交替地重复渐变。
+)
+
+// apply maps t into the period [t0, t1] according to the spread mode.
+func (s Spread) apply(t, t0, t1 float64) float64 {
+	d := t1 - t0
+	if s == SpreadPad || d <= 0 || math.IsNaN(t) || math.IsInf(t, 0) {
+		return t
+	}
+	u := math.Mod(t-t0, 2*d)
+	if u < 0 {
+		u += 2 * d
+	}
+	switch {
+	case u < d:
+	case s == SpreadRepeat:
+		u -= d
+	default:
+		u = 2*d - u
+	}
+	return t0 + u
+}
+
 // Gradient represents a color gradient that can be used as a fill or stroke pattern.
 //
 // Gradient 表示可用作填充或描边图案的颜色渐变。
 type Gradient interface {
 	Pattern
 	AddColorStop(offset float64, color color.Color)
+	SetSpread(spread Spread)
+	SetInterpolation(space Interpolation)
+	SetHueInterpolation(hue HueInterpolation)
+	SetDither(dither bool)
+}
+
+// gradient holds the color stops and options shared by all gradients.
+type gradient struct {
+	stops         stops
+	spread        Spread
+	interpolation Interpolation
+	hue           HueInterpolation
+	dither        bool
+}
+
+func (g *gradient) AddColorStop(offset float64, color color.Color) {
+	r, gr, b, a := color.RGBA()
+	g.stops = append(g.stops, stop{pos: offset, color: color, rgba: rgba64(r, gr, b, a)})
+	sort.Sort(g.stops)
+}
+
+func (g *gradient) SetSpread(spread Spread) {
+	g.spread = spread
+}
+
+// parametric is implemented by gradients to map the pixel (x, y) to its
+// position in the gradient, after spreading. ok is false if the gradient
+// does not cover the pixel.
+type parametric interface {
+	param(x, y float64) (t float64, ok bool)
+}
+
+// sample returns the color of the gradient p at the pixel (x, y).
+func (g *gradient) sample(p parametric, x, y float64) color.Color {
+	if len(g.stops) == 0 {
+		return color.Transparent
+	}
+	t, ok := p.param(x, y)
+	if !ok {
+		return color.Transparent
+	}
+	return g.getColor(t, x, y)
+}
+
+// deepSpanPattern is implemented by the patterns that can fill spans with
+// 16 bits of precision for surfaces deeper than *image.RGBA.
+type deepSpanPattern interface {
+	fillSpanDeep(dst []color.RGBA64, x, y int)
+}
+
+// fillSpan fills dst with the colors of the gradient p in row y starting
+// at x0, without going through color.Color. Unless deep is set, the colors
+// are the 8-bit colors of ColorAt.
+func (g *gradient) fillSpan(p parametric, dst []color.RGBA64, x0, y int, deep bool) {
+	fy := float64(y)
+	g.samplePoints(p, dst, func(i int) (float64, float64, bool) {
+		return float64(x0 + i), fy, true
+	}, deep)
+}
+
+// samplePoints is fillSpan for the points returned by at, which may lie
+// anywhere.
+func (g *gradient) samplePoints(p parametric, dst []color.RGBA64, at func(i int) (x, y float64, ok bool), deep bool) {
+	if len(g.stops) == 0 {
+		clear(dst)
+		return
+	}
+	for i := range dst {
+		fx, fy, ok := at(i)
+		var t float64
+		if ok {
+			t, ok = p.param(fx, fy)
+		}
+		if !ok {
+			dst[i] = color.RGBA64{}
+			continue
+		}
+		dst[i] = g.getRGBA64(t, fx, fy, deep)
+	}
+}
+
+// getRGBA64 is getColor returning the premultiplied components, with 16
+// bits of precision if deep is set.
+func (g *gradient) getRGBA64(pos, x, y float64, deep bool) color.RGBA64 {
+	if deep && g.interpolation != InterpolationPremultiplied {
+		i, j, t := segment(pos, g.stops)
+		return g.interpolation.mix(g.stops[i].color, g.stops[j].color, t, g.hue).quantize64()
+	}
+	if g.interpolation != InterpolationPremultiplied || g.dither && !deep {
+		return rgba64(g.getColor(pos, x, y).RGBA())
+	}
+	i, j, t := segment(pos, g.stops)
+	c0 := g.stops[i].rgba
+	if t == 0 {
+		return c0
+	}
+	c1 := g.stops[j].rgba
+	if deep {
+		l := func(a, b uint16) uint16 { return uint16(float64(a)*(1-t) + float64(b)*t + 0.5) }
+		return color.RGBA64{l(c0.R, c1.R), l(c0.G, c1.G), l(c0.B, c1.B), l(c0.A, c1.A)}
+	}
+	// same as colorLerp
+	l := func(a, b uint16) uint16 { return uint16(lerp(uint32(a), uint32(b), t)) * 0x101 }
+	return color.RGBA64{l(c0.R, c1.R), l(c0.G, c1.G), l(c0.B, c1.B), l(c0.A, c1.A)}
+}
+
+func rgba64(r, g, b, a uint32) color.RGBA64 {
+	return color.RGBA64{uint16(r), uint16(g), uint16(b), uint16(a)}
 }
 
 // Linear Gradient
 type linearGradient struct {
+	gradient
 	x0, y0, x1, y1 float64
-	stops          stops
 }
 
 func (g *linearGradient) ColorAt(x, y int) color.Color {
-	if len(g.stops) == 0 {
-		return color.Transparent
-	}
+	return g.colorAtF(float64(x), float64(y))
+}
+
+func (g *linearGradient) colorAtF(x, y float64) color.Color {
+	return g.sample(g, x, y)
+}
+
+// FillSpan satisfies the SpanPattern interface.
+func (g *linearGradient) FillSpan(dst []color.RGBA64, x, y int) {
+	g.fillSpan(g, dst, x, y, false)
+}
+
+func (g *linearGradient) fillSpanDeep(dst []color.RGBA64, x, y int) {
+	g.fillSpan(g, dst, x, y, true)
+}
+
+func (g *linearGradient) fillPoints(dst []color.RGBA64, at func(i int) (x, y float64, ok bool), deep bool) {
+	g.samplePoints(g, dst, at, deep)
+}
 
-	fx, fy := float64(x), float64(y)
+func (g *linearGradient) param(fx, fy float64) (float64, bool) {
 	x0, y0, x1, y1 := g.x0, g.y0, g.x1, g.y1
 	dx, dy := x1-x0, y1-y0
 
 	// Horizontal
 	if dy == 0 && dx != 0 {
-		return getColor((fx-x0)/dx, g.stops)
+		return g.spread.apply((fx-x0)/dx, 0, 1), true
 	}
 
 	// Vertical
 	if dx == 0 && dy != 0 {
-		return getColor((fy-y0)/dy, g.stops)
+		return g.spread.apply((fy-y0)/dy, 0, 1), true
 	}
 
 	// Dot product
 	s0 := dx*(fx-x0) + dy*(fy-y0)
+	if g.spread != SpreadPad {
+		// the projection is exact on pixels lying on a period boundary
+		return g.spread.apply(s0/(dx*dx+dy*dy), 0, 1), true
+	}
 	if s0 < 0 {
-		return g.stops[0].color
+		return 0, true
 	}
 	// Calculate distance to (x0,y0) alone (x0,y0)->(x1,y1)
 	mag := math.Hypot(dx, dy)
 	u := ((fx-x0)*-dy + (fy-y0)*dx) / (mag * mag)
 	x2, y2 := x0+u*-dy, y0+u*dx
 	d := math.Hypot(fx-x2, fy-y2) / mag
-	return getColor(d, g.stops)
-}
-
-func (g *linearGradient) AddColorStop(offset float64, color color.Color) {
-	g.stops = append(g.stops, stop{pos: offset, color: color})
-	sort.Sort(g.stops)
+	return d, true
 }
 
 // NewLinearGradient creates a linear gradient between (x0, y0) and (x1, y1).
@@ -96,10 +256,10 @@ type circle struct {
 }
 
 type radialGradient struct {
+	gradient
 	c0, c1, cd circle
 	a, inva    float64
 	mindr      float64
-	stops      stops
 }
 
 func dot3(x0, y0, z0, x1, y1, z1 float64) float64 {
@@ -107,25 +267,42 @@ func dot3(x0, y0, z0, x1, y1, z1 float64) float64 {
 }
 
 func (g *radialGradient) ColorAt(x, y int) color.Color {
-	if len(g.stops) == 0 {
-		return color.Transparent
-	}
+	return g.colorAtF(float64(x), float64(y))
+}
+
+func (g *radialGradient) colorAtF(x, y float64) color.Color {
+	return g.sample(g, x, y)
+}
+
+// FillSpan satisfies the SpanPattern interface.
+func (g *radialGradient) FillSpan(dst []color.RGBA64, x, y int) {
+	g.fillSpan(g, dst, x, y, false)
+}
+
+func (g *radialGradient) fillSpanDeep(dst []color.RGBA64, x, y int) {
+	g.fillSpan(g, dst, x, y, true)
+}
+
+func (g *radialGradient) fillPoints(dst []color.RGBA64, at func(i int) (x, y float64, ok bool), deep bool) {
+	g.samplePoints(g, dst, at, deep)
+}
 
+func (g *radialGradient) param(x, y float64) (float64, bool) {
 	// copy from pixman's pixman-radial-gradient.c
 
-	dx, dy := float64(x)+0.5-g.c0.x, float64(y)+0.5-g.c0.y
+	dx, dy := x+0.5-g.c0.x, y+0.5-g.c0.y
 	b := dot3(dx, dy, g.c0.r, g.cd.x, g.cd.y, g.cd.r)
 	c := dot3(dx, dy, -g.c0.r, dx, dy, g.c0.r)
 
 	if g.a == 0 {
 		if b == 0 {
-			return color.Transparent
+			return 0, false
 		}
 		t := 0.5 * c / b
 		if t*g.cd.r >= g.mindr {
-			return getColor(t, g.stops)
+			return g.spread.apply(t, 0, 1), true
 		}
-		return color.Transparent
+		return 0, false
 	}
 
 	discr := dot3(b, g.a, 0, b, -c, 0)
@@ -135,18 +312,13 @@ func (g *radialGradient) ColorAt(x, y int) color.Color {
 		t1 := (b - sqrtdiscr) * g.inva
 
 		if t0*g.cd.r >= g.mindr {
-			return getColor(t0, g.stops)
+			return g.spread.apply(t0, 0, 1), true
 		} else if t1*g.cd.r >= g.mindr {
-			return getColor(t1, g.stops)
+			return g.spread.apply(t1, 0, 1), true
 		}
 	}
 
-	return color.Transparent
-}
-
-func (g *radialGradient) AddColorStop(offset float64, color color.Color) {
-	g.stops = append(g.stops, stop{pos: offset, color: color})
-	sort.Sort(g.stops)
+	return 0, false
 }
 
 // NewRadialGradient creates a radial gradient between two circles.
@@ -175,31 +347,49 @@ func NewRadialGradient(x0, y0, r0, x1, y1, r1 float64) Gradient {
 
 // Conic Gradient
 type conicGradient struct {
+	gradient
 	cx, cy   float64
 	rotation float64
-	stops    stops
 }
 
 func (g *conicGradient) ColorAt(x, y int) color.Color {
-	if len(g.stops) == 0 {
-		return color.Transparent
-	}
-	a := math.Atan2(float64(y)-g.cy, float64(x)-g.cx)
+	return g.colorAtF(float64(x), float64(y))
+}
+
+func (g *conicGradient) colorAtF(x, y float64) color.Color {
+	return g.sample(g, x, y)
+}
+
+// FillSpan satisfies the SpanPattern interface.
+func (g *conicGradient) FillSpan(dst []color.RGBA64, x, y int) {
+	g.fillSpan(g, dst, x, y, false)
+}
+
+func (g *conicGradient) fillSpanDeep(dst []color.RGBA64, x, y int) {
+	g.fillSpan(g, dst, x, y, true)
+}
+
+func (g *conicGradient) fillPoints(dst []color.RGBA64, at func(i int) (x, y float64, ok bool), deep bool) {
+	g.samplePoints(g, dst, at, deep)
+}
+
+func (g *conicGradient) param(x, y float64) (float64, bool) {
+	a := math.Atan2(y-g.cy, x-g.cx)
 	t := norm(a, -math.Pi, math.Pi) - g.rotation
 	if t < 0 {
 		t++
 	}
-	return getColor(t, g.stops)
-}
-
-func (g *conicGradient) AddColorStop(offset float64, color color.Color) {
-	g.stops = append(g.stops, stop{pos: offset, color: color})
-	sort.Sort(g.stops)
+	// t never leaves [0, 1), so the gradient spreads over its stops instead,
+	// like a CSS repeating-conic-gradient
+	return g.spread.apply(t, g.stops[0].pos, g.stops[len(g.stops)-1].pos), true
 }
 
 // NewConicGradient creates a conic gradient centered at (cx, cy) with the given rotation in degrees.
+// As the angle always covers a full turn, repeat and reflect spread modes
+// repeat the range between the first and the last color stop.
 //
 // NewConicGradient 创建以 (cx, cy) 为中心、指定旋转角度（度）的锥形渐变。
+// 由于角度总是覆盖整圈，重复和镜像延伸模式会重复第一个与最后一个色标之间的范围。
 func NewConicGradient(cx, cy, deg float64) Gradient {
 	g := &conicGradient{
 		cx:       cx,
@@ -223,24 +413,34 @@ func norm(value, a, b float64) float64 {
 }
 
 func getColor(pos float64, stops stops) color.Color {
+	i, j, t := segment(pos, stops)
+	if t == 0 {
+		return stops[i].color
+	}
+	return colorLerp(stops[i].color, stops[j].color, t)
+}
+
+// segment returns the indices of the stops around pos and the relative
+// position of pos between them.
+func segment(pos float64, stops stops) (i, j int, t float64) {
 	if pos <= 0.0 || len(stops) == 1 {
-		return stops[0].color
+		return 0, 0, 0
 	}
 
-	last := stops[len(stops)-1]
+	last := len(stops) - 1
 
-	if pos >= last.pos {
-		return last.color
+	if pos >= stops[last].pos {
+		return last, last, 0
 	}
 
 	for i, stop := range stops[1:] {
 		if pos < stop.pos {
 			pos = (pos - stops[i].pos) / (stop.pos - stops[i].pos)
-			return colorLerp(stops[i].color, stop.color, pos)
+			return i, i + 1, pos
 		}
 	}
 
-	return last.color
+	return last, last, 0
 }
 
 func colorLerp(c0, c1 color.Color, t float64) color.Color {
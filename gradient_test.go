@@ -0,0 +1,160 @@
+package gg
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestSpreadApply(t *testing.T) {
+	tests := []struct {
+		spread Spread
+		in     float64
+		want   float64
+	}{
+		{SpreadPad, -0.5, -0.5},
+		{SpreadPad, 1.5, 1.5},
+		{SpreadRepeat, 0.25, 0.25},
+		{SpreadRepeat, 1.25, 0.25},
+		{SpreadRepeat, -0.25, 0.75},
+		{SpreadRepeat, 2.75, 0.75},
+		{SpreadReflect, 0.25, 0.25},
+		{SpreadReflect, 1.25, 0.75},
+		{SpreadReflect, -0.25, 0.25},
+		{SpreadReflect, 2.25, 0.25},
+	}
+	for _, tt := range tests {
+		if got := tt.spread.apply(tt.in, 0, 1); got != tt.want {
+			t.Errorf("Spread(%d).apply(%v) = %v, want %v", tt.spread, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGradientSpread(t *testing.T) {
+	newGradients := func() []Gradient {
+		return []Gradient{
+			NewLinearGradient(10, 0, 30, 0),
+			NewLinearGradient(10, 10, 30, 30),
+			NewRadialGradient(0, 0, 10.5, 0, 0, 30.5),
+		}
+	}
+	// points at t = -0.25, 0.25, 0.75 and 1.25 of each gradient, the radial
+	// one samples at pixel centers
+	points := [][4][2]int{
+		{{5, 0}, {15, 0}, {25, 0}, {35, 0}},
+		{{5, 5}, {15, 15}, {25, 25}, {35, 35}},
+		{{5, 0}, {15, 0}, {25, 0}, {35, 0}},
+	}
+	for _, spread := range []Spread{SpreadPad, SpreadRepeat, SpreadReflect} {
+		for i, g := range newGradients() {
+			g.AddColorStop(0, color.Black)
+			g.AddColorStop(1, color.White)
+			g.SetSpread(spread)
+			var c [4]color.Color
+			for j, p := range points[i] {
+				c[j] = g.ColorAt(p[0], p[1])
+			}
+			var want [4]color.Color
+			switch spread {
+			case SpreadPad:
+				want = [4]color.Color{color.Black, c[1], c[2], color.White}
+			case SpreadRepeat:
+				want = [4]color.Color{c[2], c[1], c[2], c[1]}
+			case SpreadReflect:
+				want = [4]color.Color{c[1], c[1], c[2], c[2]}
+			}
+			for j := range c {
+				if !sameColor(c[j], want[j]) {
+					t.Errorf("spread %d, gradient %d, point %d: got %v, want %v", spread, i, j, c[j], want[j])
+				}
+			}
+		}
+	}
+}
+
+func TestConicGradientSpread(t *testing.T) {
+	g := NewConicGradient(0, 0, 0)
+	g.AddColorStop(0, color.Black)
+	g.AddColorStop(0.25, color.White)
+	g.SetSpread(SpreadRepeat)
+	// a quarter turn apart, the colors repeat
+	if a, b := g.ColorAt(10, 3), g.ColorAt(-3, 10); !sameColor(a, b) {
+		t.Errorf("repeat: got %v and %v", a, b)
+	}
+	g.SetSpread(SpreadReflect)
+	// mirrored at the end of every period
+	if a, b := g.ColorAt(10, 3), g.ColorAt(10, -3); !sameColor(a, b) {
+		t.Errorf("reflect: got %v and %v", a, b)
+	}
+}
+
+func sameColor(a, b color.Color) bool {
+	r0, g0, b0, a0 := a.RGBA()
+	r1, g1, b1, a1 := b.RGBA()
+	d := func(x, y uint32) bool { return x-y+0x200 <= 0x400 }
+	return d(r0, r1) && d(g0, g1) && d(b0, b1) && d(a0, a1)
+}
+
+func TestGradientInterpolation(t *testing.T) {
+	red, green, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 255, 0, 255}, color.RGBA{0, 0, 255, 255}
+	tests := []struct {
+		space  Interpolation
+		hue    HueInterpolation
+		c0, c1 color.Color
+		want   color.RGBA
+	}{
+		{InterpolationPremultiplied, HueShorter, red, green, color.RGBA{127, 127, 0, 255}},
+		{InterpolationStraight, HueShorter, red, green, color.RGBA{128, 128, 0, 255}},
+		{InterpolationLinearRGB, HueShorter, red, green, color.RGBA{188, 188, 0, 255}},
+		{InterpolationHSL, HueShorter, red, blue, color.RGBA{255, 0, 255, 255}},
+		{InterpolationHSL, HueLonger, red, blue, color.RGBA{0, 255, 0, 255}},
+		{InterpolationHSL, HueIncreasing, red, blue, color.RGBA{0, 255, 0, 255}},
+		{InterpolationHSL, HueDecreasing, red, blue, color.RGBA{255, 0, 255, 255}},
+		// transparent stops keep the hue of the opaque one
+		{InterpolationStraight, HueShorter, red, color.Transparent, color.RGBA{64, 0, 0, 128}},
+		{InterpolationLinearRGB, HueShorter, red, color.Transparent, color.RGBA{128, 0, 0, 128}},
+		{InterpolationOKLab, HueShorter, red, color.Transparent, color.RGBA{128, 0, 0, 128}},
+		{InterpolationOKLCH, HueShorter, red, color.Transparent, color.RGBA{128, 0, 0, 128}},
+		{InterpolationHSL, HueShorter, red, color.Transparent, color.RGBA{128, 0, 0, 128}},
+		// gray has no hue in OKLCH, so only the lightness changes
+		{InterpolationOKLCH, HueShorter, color.Black, color.White, color.RGBA{99, 99, 99, 255}},
+	}
+	for i, tt := range tests {
+		g := NewLinearGradient(0, 0, 100, 0)
+		g.AddColorStop(0, tt.c0)
+		g.AddColorStop(1, tt.c1)
+		g.SetInterpolation(tt.space)
+		g.SetHueInterpolation(tt.hue)
+		if c := g.ColorAt(50, 0); !sameColor(c, tt.want) {
+			t.Errorf("%d: got %v, want %v", i, c, tt.want)
+		}
+		// the stops themselves are reproduced exactly
+		for x, want := range map[int]color.Color{0: tt.c0, 100: tt.c1} {
+			if c := g.ColorAt(x, 0); color.RGBAModel.Convert(c) != color.RGBAModel.Convert(want) {
+				t.Errorf("%d: got %v at stop %d, want %v", i, c, x, want)
+			}
+		}
+	}
+}
+
+func TestGradientDither(t *testing.T) {
+	g := NewLinearGradient(0, 0, 1000, 0)
+	g.AddColorStop(0, color.Black)
+	g.AddColorStop(1, color.RGBA{16, 16, 16, 255})
+	g.SetDither(true)
+	for x := 0; x < 1000; x += 100 {
+		want := 16 * float64(x) / 1000
+		var sum float64
+		for y := range 4 {
+			for i := range 4 {
+				r, _, _, _ := g.ColorAt(x+i, y).RGBA()
+				if d := float64(r>>8) - want; d < -1.5 || d > 1.5 {
+					t.Fatalf("(%d, %d): got %d, want about %.2f", x+i, y, r>>8, want)
+				}
+				sum += float64(r >> 8)
+			}
+		}
+		if d := sum/16 - want; d < -0.25 || d > 0.25 {
+			t.Errorf("x = %d: mean %.2f, want %.2f", x, sum/16, want)
+		}
+	}
+}
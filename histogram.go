@@ -0,0 +1,374 @@
+package gg
+
+import (
+	"image"
+	"math"
+	"sort"
+
+	"golang.org/x/image/draw"
+
+	"github.com/FloatTech/gg/internal/pixop"
+)
+
+// Histogram holds the number of pixels with every value of the straight
+// (not premultiplied) red, green, blue and alpha components, and of the
+// Rec. 709 luma. Fully transparent pixels only count towards A.
+//
+// Histogram 保存直通（非预乘）红、绿、蓝、alpha 分量以及 Rec. 709 亮度每个取值的像素数。
+// 完全透明的像素只计入 A。
+type Histogram struct {
+	R, G, B, A, Luma [256]int
+}
+
+// NewHistogram returns the histogram of the image im.
+//
+// NewHistogram 返回图像 im 的直方图。
+func NewHistogram(im image.Image) *Histogram {
+	src, ok := im.(*image.NRGBA)
+	if !ok {
+		src = image.NewNRGBA(im.Bounds())
+		draw.Draw(src, src.Rect, im, src.Rect.Min, draw.Src)
+	}
+	h := &Histogram{}
+	w := 4 * src.Rect.Dx()
+	for y := range src.Rect.Dy() {
+		row := src.Pix[y*src.Stride : y*src.Stride+w]
+		for i := 0; i < len(row); i += 4 {
+			p := row[i : i+4 : i+4]
+			h.A[p[3]]++
+			if p[3] == 0 {
+				continue
+			}
+			h.R[p[0]]++
+			h.G[p[1]]++
+			h.B[p[2]]++
+			h.Luma[luma709(p)]++
+		}
+	}
+	return h
+}
+
+// Histogram returns the histogram of the image of the context.
+//
+// Histogram 返回上下文图像的直方图。
+func (dc *Context) Histogram() *Histogram {
+	return NewHistogram(dc.im)
+}
+
+// luma709 returns the Rec. 709 luma of the straight pixel p.
+func luma709(p []uint8) uint8 {
+	w := pixop.Rec709
+	return uint8(w[0]*float64(p[0]) + w[1]*float64(p[1]) + w[2]*float64(p[2]) + 0.5)
+}
+
+// histTotal returns the number of pixels counted in hist.
+func histTotal(hist *[256]int) (n int) {
+	for _, c := range hist {
+		n += c
+	}
+	return
+}
+
+// histRange returns the lowest and highest values of hist after ignoring
+// the fraction clip of the pixels at each end.
+func histRange(hist *[256]int, clip float64) (lo, hi int) {
+	skip := int(max(0, min(0.5, clip)) * float64(histTotal(hist)))
+	lo, hi = 0, 255
+	for n := 0; lo < 255; lo++ {
+		if n += hist[lo]; n > skip {
+			break
+		}
+	}
+	for n := 0; hi > 0; hi-- {
+		if n += hist[hi]; n > skip {
+			break
+		}
+	}
+	return
+}
+
+// histMean returns the mean value of hist, or 0 for an empty histogram.
+func histMean(hist *[256]int) float64 {
+	var sum, n float64
+	for v, c := range hist {
+		sum += float64(v * c)
+		n += float64(c)
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / n
+}
+
+// stretchTransfer returns the transfer function that maps [lo, hi] to
+// [0, 255], or nil if the range is empty.
+func stretchTransfer(lo, hi int) Transfer {
+	if hi <= lo {
+		return nil
+	}
+	a, b := float64(lo)/255, float64(hi)/255
+	return func(v float64) float64 {
+		return (v - a) / (b - a)
+	}
+}
+
+// AutoContrast returns a filter that stretches the colors of the image to
+// the full range, with the same mapping for the red, green and blue
+// components, so the hues are kept. clip is the fraction of the pixels
+// ignored at each end of the range, like 0.005.
+//
+// AutoContrast 返回将图像颜色拉伸到完整范围的滤镜，红、绿、蓝分量使用同一映射，因此保持色相。
+// clip 为在范围两端忽略的像素比例，例如 0.005。
+func AutoContrast(clip float64) Filter {
+	return FilterFunc(func(src *image.NRGBA) *image.NRGBA {
+		h := NewHistogram(src)
+		lo, hi := 255, 0
+		for _, hist := range []*[256]int{&h.R, &h.G, &h.B} {
+			l, u := histRange(hist, clip)
+			lo, hi = min(lo, l), max(hi, u)
+		}
+		f := stretchTransfer(lo, hi)
+		return ComponentTransfer(f, f, f, nil).Filter(src)
+	})
+}
+
+// AutoLevels returns a filter that stretches the red, green and blue
+// components of the image to the full range each, which also removes color
+// casts. clip is the fraction of the pixels ignored at each end of the
+// range, like 0.005.
+//
+// AutoLevels 返回将图像的红、绿、蓝分量分别拉伸到完整范围的滤镜，同时会去除偏色。
+// clip 为在范围两端忽略的像素比例，例如 0.005。
+func AutoLevels(clip float64) Filter {
+	return FilterFunc(func(src *image.NRGBA) *image.NRGBA {
+		h := NewHistogram(src)
+		var f [3]Transfer
+		for c, hist := range []*[256]int{&h.R, &h.G, &h.B} {
+			f[c] = stretchTransfer(histRange(hist, clip))
+		}
+		return ComponentTransfer(f[0], f[1], f[2], nil).Filter(src)
+	})
+}
+
+// AutoWhiteBalance returns a filter that removes the color cast of the
+// image by scaling the red, green and blue components so that their means
+// are equal to the mean luma (the gray world assumption).
+//
+// AutoWhiteBalance 返回去除图像偏色的滤镜：按灰度世界假设缩放红、绿、蓝分量，
+// 使它们的均值等于平均亮度。
+func AutoWhiteBalance() Filter {
+	return FilterFunc(func(src *image.NRGBA) *image.NRGBA {
+		h := NewHistogram(src)
+		gray := histMean(&h.Luma)
+		var f [3]Transfer
+		for c, hist := range []*[256]int{&h.R, &h.G, &h.B} {
+			if m := histMean(hist); m > 0 {
+				f[c] = TransferLinear(gray/m, 0)
+			}
+		}
+		return ComponentTransfer(f[0], f[1], f[2], nil).Filter(src)
+	})
+}
+
+// equalizeTable returns the table that spreads the values of hist evenly
+// over the full range, limiting the count of every value to limit first
+// if it is positive.
+func equalizeTable(hist *[256]int, limit int) (table [256]uint8) {
+	counts := *hist
+	if limit > 0 {
+		// redistribute the clipped counts over all values
+		excess := 0
+		for v, c := range counts {
+			if c > limit {
+				excess += c - limit
+				counts[v] = limit
+			}
+		}
+		for v := range counts {
+			counts[v] += excess / 256
+			if v < excess%256 {
+				counts[v]++
+			}
+		}
+	}
+	total := histTotal(&counts)
+	first := 0
+	for _, c := range counts {
+		if c > 0 {
+			first = c
+			break
+		}
+	}
+	if total == first {
+		for v := range table {
+			table[v] = uint8(v)
+		}
+		return
+	}
+	cdf := 0
+	for v, c := range counts {
+		cdf += c
+		table[v] = uint8(math.Round(255 * float64(max(cdf-first, 0)) / float64(total-first)))
+	}
+	return
+}
+
+// mapLuma returns a copy of src whose luma is changed to the value that f
+// returns for the pixel at (x, y) relative to the image with the luma l.
+// The chroma is kept by shifting the red, green and blue components alike.
+func mapLuma(src *image.NRGBA, f func(x, y int, l uint8) float64) *image.NRGBA {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewNRGBA(src.Rect)
+	pixop.Rows(Workers(), h, func(y0, y1 int) {
+		for y := y0; y < y1; y++ {
+			for x := range w {
+				s := src.Pix[y*src.Stride+4*x : y*src.Stride+4*x+4]
+				d := dst.Pix[y*dst.Stride+4*x : y*dst.Stride+4*x+4]
+				l := luma709(s)
+				shift := f(x, y, l) - float64(l)
+				for c := range 3 {
+					d[c] = uint8(max(0, min(255, float64(s[c])+shift+0.5)))
+				}
+				d[3] = s[3]
+			}
+		}
+	})
+	return dst
+}
+
+// Equalize returns a filter that equalizes the histogram of the image,
+// spreading its luma evenly over the full range while keeping the chroma.
+//
+// Equalize 返回对图像进行直方图均衡化的滤镜，使亮度均匀分布在完整范围内，同时保持色度。
+func Equalize() Filter {
+	return FilterFunc(func(src *image.NRGBA) *image.NRGBA {
+		t := equalizeTable(&NewHistogram(src).Luma, 0)
+		return mapLuma(src, func(_, _ int, l uint8) float64 {
+			return float64(t[l])
+		})
+	})
+}
+
+// CLAHE returns a filter that applies contrast limited adaptive histogram
+// equalization to the luma of the image. The image is split into a grid of
+// tiles x tiles regions, which are equalized on their own with the counts
+// limited to clipLimit times the mean count, like 2 to 4, and blended
+// smoothly. It brings out local detail without boosting the noise in flat
+// areas. A clipLimit of 0 disables the limit.
+//
+// CLAHE 返回对图像亮度应用限制对比度自适应直方图均衡化的滤镜。图像被分成 tiles x tiles 个区域，
+// 各区域分别均衡化，计数被限制为平均计数的 clipLimit 倍（例如 2 到 4），再平滑地混合。
+// 它能突出局部细节，而不会放大平坦区域的噪声。clipLimit 为 0 时不限制。
+func CLAHE(tiles int, clipLimit float64) Filter {
+	return FilterFunc(func(src *image.NRGBA) *image.NRGBA {
+		w, h := src.Rect.Dx(), src.Rect.Dy()
+		nx, ny := max(1, min(tiles, w)), max(1, min(tiles, h))
+		tables := make([][256]uint8, nx*ny)
+		for ty := range ny {
+			for tx := range nx {
+				r := image.Rect(tx*w/nx, ty*h/ny, (tx+1)*w/nx, (ty+1)*h/ny).Add(src.Rect.Min)
+				hist := NewHistogram(src.SubImage(r)).Luma
+				limit := 0
+				if clipLimit > 0 {
+					limit = max(1, int(clipLimit*float64(histTotal(&hist))/256))
+				}
+				tables[ty*nx+tx] = equalizeTable(&hist, limit)
+			}
+		}
+		// the neighbouring tiles of v and its position between their centers
+		tile := func(v, n, size int) (i0, i1 int, t float64) {
+			f := (float64(v)+0.5)*float64(n)/float64(size) - 0.5
+			i0 = max(0, min(n-1, int(math.Floor(f))))
+			i1 = min(n-1, i0+1)
+			t = max(0, min(1, f-float64(i0)))
+			return
+		}
+		return mapLuma(src, func(x, y int, l uint8) float64 {
+			i0, i1, fx := tile(x, nx, w)
+			j0, j1, fy := tile(y, ny, h)
+			return mix(
+				mix(float64(tables[j0*nx+i0][l]), float64(tables[j0*nx+i1][l]), fx),
+				mix(float64(tables[j1*nx+i0][l]), float64(tables[j1*nx+i1][l]), fx), fy)
+		})
+	})
+}
+
+// ToneCurve returns the tone curve through the control points, which are in
+// [0, 1] on both axes, for use with Curves. The points are joined by a
+// monotone cubic spline, so the curve does not overshoot between them, and
+// it stays flat beyond the first and last points. Fewer than two points
+// give the identity curve.
+//
+// ToneCurve 返回经过控制点的色调曲线，控制点两个坐标均在 [0, 1] 内，可用于 Curves。
+// 控制点之间以单调三次样条连接，因此曲线不会过冲，在首尾控制点之外保持水平。
+// 少于两个控制点时返回恒等曲线。
+func ToneCurve(points ...Point) *[256]uint8 {
+	var curve [256]uint8
+	pts := make([]Point, 0, len(points))
+	for _, p := range points {
+		pts = append(pts, Point{max(0, min(1, p.X)), max(0, min(1, p.Y))})
+	}
+	sort.SliceStable(pts, func(i, j int) bool { return pts[i].X < pts[j].X })
+	// keep the last of the points with the same X
+	n := 0
+	for i, p := range pts {
+		if i > 0 && p.X == pts[n-1].X {
+			pts[n-1] = p
+			continue
+		}
+		pts[n] = p
+		n++
+	}
+	pts = pts[:n]
+	if n < 2 {
+		for v := range curve {
+			curve[v] = uint8(v)
+		}
+		return &curve
+	}
+	// Fritsch-Carlson tangents
+	d := make([]float64, n-1)
+	for k := range d {
+		d[k] = (pts[k+1].Y - pts[k].Y) / (pts[k+1].X - pts[k].X)
+	}
+	m := make([]float64, n)
+	m[0], m[n-1] = d[0], d[n-2]
+	for k := 1; k < n-1; k++ {
+		if d[k-1]*d[k] > 0 {
+			m[k] = (d[k-1] + d[k]) / 2
+		}
+	}
+	for k := range d {
+		if d[k] == 0 {
+			m[k], m[k+1] = 0, 0
+			continue
+		}
+		a, b := m[k]/d[k], m[k+1]/d[k]
+		if s := a*a + b*b; s > 9 {
+			t := 3 / math.Sqrt(s)
+			m[k], m[k+1] = t*a*d[k], t*b*d[k]
+		}
+	}
+	k := 0
+	for v := range curve {
+		x := float64(v) / 255
+		var y float64
+		switch {
+		case x <= pts[0].X:
+			y = pts[0].Y
+		case x >= pts[n-1].X:
+			y = pts[n-1].Y
+		default:
+			for x > pts[k+1].X {
+				k++
+			}
+			hx := pts[k+1].X - pts[k].X
+			t := (x - pts[k].X) / hx
+			t2, t3 := t*t, t*t*t
+			y = (2*t3-3*t2+1)*pts[k].Y + (t3-2*t2+t)*hx*m[k] +
+				(-2*t3+3*t2)*pts[k+1].Y + (t3-t2)*hx*m[k+1]
+		}
+		curve[v] = uint8(max(0, min(255, y*255+0.5)))
+	}
+	return &curve
+}
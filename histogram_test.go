@@ -0,0 +1,95 @@
+package gg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestHistogram(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 3, 1))
+	src.SetNRGBA(0, 0, color.NRGBA{255, 0, 0, 255})
+	src.SetNRGBA(1, 0, color.NRGBA{255, 255, 255, 128})
+	h := NewHistogram(src)
+	if h.A[0] != 1 || h.A[128] != 1 || h.A[255] != 1 {
+		t.Errorf("unexpected alpha histogram")
+	}
+	if h.R[255] != 2 || h.G[0] != 1 || h.G[255] != 1 || h.Luma[54] != 1 || h.Luma[255] != 1 {
+		t.Errorf("transparent pixels should only count towards A")
+	}
+
+	dc := NewContext(10, 10)
+	dc.SetRGB255(0, 0, 255)
+	dc.Clear()
+	if h := dc.Histogram(); h.B[255] != 100 || h.Luma[18] != 100 {
+		t.Errorf("unexpected context histogram")
+	}
+}
+
+func TestAutoAdjust(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.SetNRGBA(0, 0, color.NRGBA{50, 60, 70, 255})
+	src.SetNRGBA(1, 0, color.NRGBA{100, 200, 150, 255})
+	for _, tc := range []struct {
+		name string
+		f    Filter
+		want [2]color.NRGBA
+	}{
+		{"auto-contrast", AutoContrast(0), [2]color.NRGBA{{0, 17, 34, 255}, {85, 255, 170, 255}}},
+		{"auto-levels", AutoLevels(0), [2]color.NRGBA{{0, 0, 0, 255}, {255, 255, 255, 255}}},
+		{"equalize", Equalize(), [2]color.NRGBA{{0, 1, 11, 255}, {180, 255, 230, 255}}},
+	} {
+		out := tc.f.Filter(src)
+		for x, want := range tc.want {
+			if got := out.NRGBAAt(x, 0); !sameColor(got, want) {
+				t.Errorf("%s: pixel %d: expected %v, got %v", tc.name, x, want, got)
+			}
+		}
+	}
+
+	cast := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for i := 0; i < len(cast.Pix); i += 4 {
+		copy(cast.Pix[i:], []uint8{200, 120, 100, 255})
+	}
+	c := AutoWhiteBalance().Filter(cast).NRGBAAt(0, 0)
+	if max(c.R, c.G, c.B)-min(c.R, c.G, c.B) > 2 {
+		t.Errorf("auto white balance: expected gray, got %v", c)
+	}
+}
+
+func TestCLAHE(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 100, 60))
+	draw.Draw(src, src.Rect, gridImage(), image.Point{}, draw.Src)
+	// a single tile without clipping is plain equalization
+	if a, b := CLAHE(1, 0).Filter(src), Equalize().Filter(src); !bytes.Equal(a.Pix, b.Pix) {
+		t.Errorf("expected CLAHE(1, 0) to equal Equalize")
+	}
+	sub := src.SubImage(image.Rect(10, 10, 60, 40)).(*image.NRGBA)
+	out := CLAHE(4, 3).Filter(sub)
+	if out.Rect != sub.Rect {
+		t.Fatalf("expected bounds %v, got %v", sub.Rect, out.Rect)
+	}
+}
+
+func TestToneCurve(t *testing.T) {
+	identity := ToneCurve(Point{0, 0}, Point{1, 1})
+	for v, c := range identity {
+		if int(c) != v {
+			t.Fatalf("identity curve: expected %d at %d, got %d", v, v, c)
+		}
+	}
+	if *ToneCurve() != *identity {
+		t.Errorf("expected no points to give the identity curve")
+	}
+	s := ToneCurve(Point{0.25, 0.15}, Point{0.5, 0.5}, Point{0.75, 0.85}, Point{1, 1})
+	if s[0] != 38 || s[32] != 38 || s[128] != 128 || s[255] != 255 {
+		t.Errorf("unexpected curve values %d %d %d %d", s[0], s[32], s[128], s[255])
+	}
+	for v := 1; v < 256; v++ {
+		if s[v] < s[v-1] {
+			t.Fatalf("curve is not monotone at %d", v)
+		}
+	}
+}
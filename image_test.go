@@ -0,0 +1,82 @@
+package gg
+
+import (
+	"image"
+	"testing"
+)
+
+func gridImage() image.Image {
+	src := NewContext(100, 60)
+	src.SetRGB(1, 1, 1)
+	src.Clear()
+	for i := 10; i < 100; i += 10 {
+		x := float64(i) + 0.5
+		src.DrawLine(x, 0, x, 100)
+		src.DrawLine(0, x, 100, x)
+	}
+	src.SetRGB(0, 0, 0)
+	src.Stroke()
+	return src.Image()
+}
+
+func TestDrawImageRect(t *testing.T) {
+	im := gridImage()
+	a := NewContext(200, 200)
+	a.DrawImage(im, 50, 50)
+	b := NewContext(200, 200)
+	b.DrawImageRect(im, im.Bounds(), 50, 50, 100, 60)
+	checkHash(t, b, a.String())
+
+	// a sub-rectangle scaled up by two
+	dc := NewContext(200, 200)
+	dc.SetScaleStyle(NearestNeighbor)
+	dc.DrawImageRect(im, image.Rect(10, 10, 60, 40), 20, 30, 100, 60)
+	if err := saveImage(dc, "TestDrawImageRect"); err != nil {
+		t.Fatal(err)
+	}
+	want := im.(*image.RGBA).RGBAAt(10+7/2, 10+9/2)
+	if got := dc.im.(*image.RGBA).RGBAAt(20+7, 30+9); got != want {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestDrawImageFit(t *testing.T) {
+	im := gridImage()
+	for _, fit := range []ImageFit{FitFill, FitContain, FitCover, FitNone, FitScaleDown} {
+		dc := NewContext(200, 200)
+		dc.DrawImageFit(im, 40, 20, 80, 120, fit)
+		var b image.Rectangle
+		for y := range 200 {
+			for x := range 200 {
+				if dc.im.(*image.RGBA).RGBAAt(x, y).A != 0 {
+					b = b.Union(image.Rect(x, y, x+1, y+1))
+				}
+			}
+		}
+		var want image.Rectangle
+		switch fit {
+		case FitFill, FitCover:
+			want = image.Rect(40, 20, 120, 140)
+		case FitContain:
+			want = image.Rect(40, 56, 120, 104)
+		case FitNone:
+			want = image.Rect(40, 50, 120, 110)
+		case FitScaleDown:
+			want = image.Rect(40, 56, 120, 104)
+		}
+		if b != want {
+			t.Errorf("fit %d: expected bounds %v, got %v", fit, want, b)
+		}
+	}
+}
+
+func TestDrawImageFitClip(t *testing.T) {
+	im := gridImage()
+	dc := NewContext(200, 200)
+	dc.DrawRectangle(0, 0, 80, 200)
+	dc.Clip()
+	dc.DrawImageFit(im, 40, 20, 80, 120, FitCover)
+	if dc.im.(*image.RGBA).RGBAAt(79, 80).A == 0 || dc.im.(*image.RGBA).RGBAAt(80, 80).A != 0 || dc.im.(*image.RGBA).RGBAAt(40, 19).A != 0 {
+		t.Fatal("image should be clipped by both the clip mask and the box")
+	}
+}
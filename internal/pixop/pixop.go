@@ -0,0 +1,231 @@
+// Package pixop implements per-pixel operations on straight (not
+// premultiplied) 8-bit pixels in R, G, B, A order, shared by gg and its
+// factory package. The operations run directly on Pix slices with the rows
+// split across the number of goroutines the caller passes in.
+package pixop
+
+import (
+	"image/color"
+	"math"
+	"sync"
+)
+
+// Luma weights of red, green and blue. gg's filters follow CSS and SVG and
+// use Rec709, while the factory package keeps the Rec601 weights its
+// Grayscale has always used.
+var (
+	Rec601 = [3]float64{0.299, 0.587, 0.114}
+	Rec709 = [3]float64{0.2126, 0.7152, 0.0722}
+)
+
+// luma returns the luma of p with the weights w.
+func luma(p []uint8, w [3]float64) float64 {
+	return w[0]*float64(p[0]) + w[1]*float64(p[1]) + w[2]*float64(p[2])
+}
+
+// RGB8 returns the straight 8-bit color components of c.
+func RGB8(c color.Color) [3]uint8 {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return [3]uint8{n.R, n.G, n.B}
+}
+
+// Op changes the pixel p, which holds R, G, B and A, in place.
+type Op func(p []uint8)
+
+// Rows calls f on ranges of [0, n) concurrently on up to workers
+// goroutines.
+func Rows(workers, n int, f func(i0, i1 int)) {
+	k := min(workers, n)
+	if k <= 1 {
+		f(0, n)
+		return
+	}
+	var wg sync.WaitGroup
+	for i := range k {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f(i*n/k, (i+1)*n/k)
+		}()
+	}
+	wg.Wait()
+}
+
+// Apply applies op to every pixel of the w x h image with the given Pix
+// slice and stride, whose first pixel is at pix[0].
+func Apply(workers int, pix []uint8, stride, w, h int, op Op) {
+	Rows(workers, h, func(y0, y1 int) {
+		for y := y0; y < y1; y++ {
+			row := pix[y*stride : y*stride+4*w]
+			for i := 0; i < len(row); i += 4 {
+				op(row[i : i+4 : i+4])
+			}
+		}
+	})
+}
+
+// clamp rounds v to a component value.
+func clamp(v float64) uint8 {
+	return uint8(max(0, min(255, v+0.5)))
+}
+
+// LUT maps every component through its own table.
+type LUT [4][256]uint8
+
+// Identity returns the table that changes nothing.
+func Identity() *LUT {
+	var l LUT
+	for c := range l {
+		for v := range l[c] {
+			l[c][v] = uint8(v)
+		}
+	}
+	return &l
+}
+
+// Op returns the operation that looks the components up in l.
+func (l *LUT) Op() Op {
+	return func(p []uint8) {
+		p[0], p[1], p[2], p[3] = l[0][p[0]], l[1][p[1]], l[2][p[2]], l[3][p[3]]
+	}
+}
+
+// Transfer returns the table that maps the components of the channel c
+// through f, which works in [0, 1]. A nil f leaves them unchanged.
+func (l *LUT) Transfer(c int, f func(v float64) float64) *LUT {
+	if f != nil {
+		for v := range l[c] {
+			l[c][v] = clamp(f(float64(v)/255) * 255)
+		}
+	}
+	return l
+}
+
+// Invert returns the table that inverts the colors.
+func Invert() *LUT {
+	l := Identity()
+	for c := range 3 {
+		for v := range l[c] {
+			l[c][v] = 255 - uint8(v)
+		}
+	}
+	return l
+}
+
+// Posterize returns the table that reduces every color component to the
+// given number of evenly spaced levels.
+func Posterize(levels int) *LUT {
+	l := Identity()
+	if levels < 2 {
+		levels = 2
+	}
+	n := float64(levels - 1)
+	for c := range 3 {
+		for v := range l[c] {
+			l[c][v] = clamp(math.Round(float64(v)/255*n) / n * 255)
+		}
+	}
+	return l
+}
+
+// Levels returns the table that maps the color range [inBlack, inWhite]
+// to [outBlack, outWhite] with the gamma correction gamma in between, like
+// the levels dialog of image editors.
+func Levels(inBlack, inWhite uint8, gamma float64, outBlack, outWhite uint8) *LUT {
+	l := Identity()
+	if gamma <= 0 {
+		gamma = 1
+	}
+	span := max(float64(inWhite)-float64(inBlack), 1)
+	for v := range 256 {
+		t := max(0, min(1, (float64(v)-float64(inBlack))/span))
+		t = math.Pow(t, 1/gamma)
+		out := clamp(float64(outBlack) + t*(float64(outWhite)-float64(outBlack)))
+		for c := range 3 {
+			l[c][v] = out
+		}
+	}
+	return l
+}
+
+// Curves returns the table that maps the red, green and blue components
+// through r, g and b. A nil curve leaves its component unchanged.
+func Curves(r, g, b *[256]uint8) *LUT {
+	l := Identity()
+	for c, curve := range [3]*[256]uint8{r, g, b} {
+		if curve != nil {
+			l[c] = *curve
+		}
+	}
+	return l
+}
+
+// Gray returns the operation that replaces the color by its luma with the
+// weights w.
+func Gray(w [3]float64) Op {
+	return func(p []uint8) {
+		y := clamp(luma(p, w))
+		p[0], p[1], p[2] = y, y, y
+	}
+}
+
+// Threshold returns the operation that turns the pixels whose luma with
+// the weights w is at least level white, and the others black.
+func Threshold(level uint8, w [3]float64) Op {
+	return func(p []uint8) {
+		var v uint8
+		if luma(p, w) >= float64(level) {
+			v = 255
+		}
+		p[0], p[1], p[2] = v, v, v
+	}
+}
+
+// Duotone returns the operation that maps the luma with the weights w to
+// the gradient from the color dark to light.
+func Duotone(dark, light [3]uint8, w [3]float64) Op {
+	var lut [3][256]uint8
+	for c := range lut {
+		for v := range lut[c] {
+			t := float64(v) / 255
+			lut[c][v] = clamp(float64(dark[c])*(1-t) + float64(light[c])*t)
+		}
+	}
+	return func(p []uint8) {
+		y := clamp(luma(p, w))
+		p[0], p[1], p[2] = lut[0][y], lut[1][y], lut[2][y]
+	}
+}
+
+// Matrix returns the operation that transforms the components in [0, 1]
+// by the 4x5 matrix m in row-major order, whose last column is added.
+func Matrix(m [20]float64) Op {
+	return func(p []uint8) {
+		r, g, b, a := float64(p[0]), float64(p[1]), float64(p[2]), float64(p[3])
+		for i := range 4 {
+			k := m[i*5 : i*5+5 : i*5+5]
+			p[i] = clamp(k[0]*r + k[1]*g + k[2]*b + k[3]*a + k[4]*255)
+		}
+	}
+}
+
+// Swap returns the operation that sets the red, green, blue and alpha
+// components to the components r, g, b and a of the pixel, each one of 0
+// to 3.
+func Swap(r, g, b, a int) Op {
+	return func(p []uint8) {
+		p[0], p[1], p[2], p[3] = p[r&3], p[g&3], p[b&3], p[a&3]
+	}
+}
+
+// SepiaMatrix returns the color matrix that converts the colors to sepia
+// by amount in [0, 1], as CSS does.
+func SepiaMatrix(amount float64) [20]float64 {
+	s := 1 - max(0, min(1, amount))
+	return [20]float64{
+		0.393 + 0.607*s, 0.769 - 0.769*s, 0.189 - 0.189*s, 0, 0,
+		0.349 - 0.349*s, 0.686 + 0.314*s, 0.168 - 0.168*s, 0, 0,
+		0.272 - 0.272*s, 0.534 - 0.534*s, 0.131 + 0.869*s, 0, 0,
+		0, 0, 0, 1, 0,
+	}
+}
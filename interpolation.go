@@ -0,0 +1,343 @@
+package gg
+
+import (
+	"image/color"
+	"math"
+)
+
+// Interpolation defines the color space in which a gradient interpolates
+// between its color stops.
+//
+// Interpolation 定义渐变在色标之间进行插值时使用的颜色空间。
+type Interpolation int
+
+// Gradient interpolation spaces. Except for InterpolationStraight, colors
+// are interpolated with premultiplied alpha, as CSS does, so transparent
+// stops do not darken their neighbours.
+//
+// 渐变插值空间。除 InterpolationStraight 外，颜色均像 CSS 一样以预乘 alpha 插值，
+// 因此透明色标不会使相邻颜色变暗。
+const (
+	// InterpolationPremultiplied interpolates premultiplied sRGB components.
+	//
+	// InterpolationPremultiplied 对预乘的 sRGB 分量插值。
+	InterpolationPremultiplied Interpolation = iota
+
+	// InterpolationStraight interpolates sRGB components and alpha
+	// separately.
+	//
+	// InterpolationStraight 分别对 sRGB 分量与 alpha 插值。
+	InterpolationStraight
+
+	// InterpolationLinearRGB interpolates linear-light RGB components, which
+	// mixes light physically and gives brighter middles.
+	//
+	// InterpolationLinearRGB 对线性光 RGB 分量插值，符合物理上的光线混合，中间色更明亮。
+	InterpolationLinearRGB
+
+	// InterpolationOKLab interpolates in the perceptual OKLab space, which
+	// avoids muddy and grayish middles.
+	//
+	// InterpolationOKLab 在感知均匀的 OKLab 空间中插值，避免中间色浑浊发灰。
+	InterpolationOKLab
+
+	// InterpolationOKLCH interpolates in the polar form of OKLab, following
+	// the hue interpolation method.
+	//
+	// InterpolationOKLCH 在 OKLab 的极坐标形式中插值，色相按色相插值方式变化。
+	InterpolationOKLCH
+
+	// InterpolationHSL interpolates hue, saturation and lightness, following
+	// the hue interpolation method.
+	//
+	// InterpolationHSL 对色相、饱和度和亮度插值，色相按色相插值方式变化。
+	InterpolationHSL
+)
+
+// HueInterpolation defines which way around the color wheel the hue goes
+// in the polar interpolation spaces, like the CSS hue-interpolation-method.
+//
+// HueInterpolation 定义极坐标插值空间中色相在色轮上的变化方向，与 CSS 的 hue-interpolation-method 相同。
+type HueInterpolation int
+
+// Hue interpolation methods.
+//
+// 色相插值方式。
+const (
+	HueShorter    HueInterpolation = iota // Take the shorter arc. 取较短的弧。
+	HueLonger                             // Take the longer arc. 取较长的弧。
+	HueIncreasing                         // Always increase the hue. 色相始终增加。
+	HueDecreasing                         // Always decrease the hue. 色相始终减少。
+)
+
+func (g *gradient) SetInterpolation(space Interpolation) {
+	g.interpolation = space
+}
+
+func (g *gradient) SetHueInterpolation(hue HueInterpolation) {
+	g.hue = hue
+}
+
+func (g *gradient) SetDither(dither bool) {
+	g.dither = dither
+}
+
+// getColor returns the color at position pos for the pixel (x, y).
+func (g *gradient) getColor(pos, x, y float64) color.Color {
+	if g.interpolation == InterpolationPremultiplied && !g.dither {
+		return getColor(pos, g.stops)
+	}
+	i, j, t := segment(pos, g.stops)
+	var d float64
+	if g.dither {
+		d = bayer4[int(math.Floor(y))&3][int(math.Floor(x))&3]
+	}
+	return g.interpolation.mix(g.stops[i].color, g.stops[j].color, t, g.hue).quantize(d)
+}
+
+// bayer4 holds the thresholds of a 4x4 ordered dither in (-0.5, 0.5).
+var bayer4 = func() (m [4][4]float64) {
+	b := [4][4]int{{0, 8, 2, 10}, {12, 4, 14, 6}, {3, 11, 1, 9}, {15, 7, 13, 5}}
+	for y := range b {
+		for x := range b[y] {
+			m[y][x] = (float64(b[y][x])+0.5)/16 - 0.5
+		}
+	}
+	return
+}()
+
+// fcolor is a color with straight alpha and components in [0, 1].
+type fcolor struct {
+	r, g, b, a float64
+}
+
+func toFColor(c color.Color) fcolor {
+	r, g, b, a := c.RGBA()
+	if a == 0 {
+		return fcolor{}
+	}
+	fa := float64(a)
+	return fcolor{float64(r) / fa, float64(g) / fa, float64(b) / fa, fa / 0xffff}
+}
+
+// quantize converts c to an 8-bit premultiplied color, adding d to every
+// component before rounding.
+func (c fcolor) quantize(d float64) color.RGBA {
+	q := func(v float64) uint8 {
+		return uint8(max(0, min(255, math.Floor(v*255+0.5+d))))
+	}
+	a := q(c.a)
+	clamp := func(v float64) float64 { return max(0, min(1, v)) }
+	return color.RGBA{
+		min(q(clamp(c.r)*c.a), a),
+		min(q(clamp(c.g)*c.a), a),
+		min(q(clamp(c.b)*c.a), a),
+		a,
+	}
+}
+
+// quantize64 converts c to a 16-bit premultiplied color.
+func (c fcolor) quantize64() color.RGBA64 {
+	q := func(v float64) uint16 {
+		return uint16(max(0, min(0xffff, math.Floor(v*0xffff+0.5))))
+	}
+	a := q(c.a)
+	clamp := func(v float64) float64 { return max(0, min(1, v)) }
+	return color.RGBA64{
+		min(q(clamp(c.r)*c.a), a),
+		min(q(clamp(c.g)*c.a), a),
+		min(q(clamp(c.b)*c.a), a),
+		a,
+	}
+}
+
+// hueIndex returns the index of the hue among the components of the space,
+// or -1 if it is not polar.
+func (s Interpolation) hueIndex() int {
+	switch s {
+	case InterpolationOKLCH:
+		return 2
+	case InterpolationHSL:
+		return 0
+	}
+	return -1
+}
+
+// mix interpolates between c0 and c1 at t in the space s.
+func (s Interpolation) mix(c0, c1 color.Color, t float64, hue HueInterpolation) fcolor {
+	f0, f1 := toFColor(c0), toFColor(c1)
+	if t == 0 {
+		return f0
+	}
+	a := f0.a + (f1.a-f0.a)*t
+	if a == 0 {
+		return fcolor{}
+	}
+	v0, v1 := s.from(f0), s.from(f1)
+	h := s.hueIndex()
+	if h >= 0 {
+		v0[h], v1[h] = fixHues(v0[h], v1[h], hue)
+	}
+	var v [3]float64
+	for i := range v {
+		switch {
+		case i == h:
+			v[i] = math.Mod(v0[i]+(v1[i]-v0[i])*t, 360)
+		case s == InterpolationStraight:
+			v[i] = v0[i] + (v1[i]-v0[i])*t
+		default:
+			v[i] = (v0[i]*f0.a + (v1[i]*f1.a-v0[i]*f0.a)*t) / a
+		}
+	}
+	c := s.to(v)
+	c.a = a
+	return c
+}
+
+// fixHues resolves missing (NaN) hues and adjusts h0 and h1 so that
+// interpolating linearly between them follows the hue method.
+func fixHues(h0, h1 float64, hue HueInterpolation) (float64, float64) {
+	switch {
+	case math.IsNaN(h0) && math.IsNaN(h1):
+		return 0, 0
+	case math.IsNaN(h0):
+		return h1, h1
+	case math.IsNaN(h1):
+		return h0, h0
+	}
+	d := h1 - h0
+	switch hue {
+	case HueShorter:
+		if d > 180 {
+			h0 += 360
+		} else if d < -180 {
+			h1 += 360
+		}
+	case HueLonger:
+		if d > 0 && d < 180 {
+			h0 += 360
+		} else if d > -180 && d <= 0 {
+			h1 += 360
+		}
+	case HueIncreasing:
+		if d < 0 {
+			h1 += 360
+		}
+	case HueDecreasing:
+		if d > 0 {
+			h0 += 360
+		}
+	}
+	return h0, h1
+}
+
+// from converts the straight sRGB color c to the components of the space.
+// Missing hues are NaN.
+func (s Interpolation) from(c fcolor) [3]float64 {
+	switch s {
+	case InterpolationLinearRGB:
+		return [3]float64{srgbToLinear(c.r), srgbToLinear(c.g), srgbToLinear(c.b)}
+	case InterpolationOKLab:
+		return linearToOKLab(srgbToLinear(c.r), srgbToLinear(c.g), srgbToLinear(c.b))
+	case InterpolationOKLCH:
+		lab := linearToOKLab(srgbToLinear(c.r), srgbToLinear(c.g), srgbToLinear(c.b))
+		ch := math.Hypot(lab[1], lab[2])
+		h := math.NaN()
+		if ch > 1e-6 {
+			h = math.Mod(math.Atan2(lab[2], lab[1])*180/math.Pi+360, 360)
+		}
+		return [3]float64{lab[0], ch, h}
+	case InterpolationHSL:
+		return rgbToHSL(c.r, c.g, c.b)
+	}
+	return [3]float64{c.r, c.g, c.b}
+}
+
+// to converts the components of the space back to straight sRGB.
+func (s Interpolation) to(v [3]float64) fcolor {
+	switch s {
+	case InterpolationLinearRGB:
+		return fcolor{r: linearToSRGB(v[0]), g: linearToSRGB(v[1]), b: linearToSRGB(v[2])}
+	case InterpolationOKLab:
+		r, g, b := okLabToLinear(v)
+		return fcolor{r: linearToSRGB(r), g: linearToSRGB(g), b: linearToSRGB(b)}
+	case InterpolationOKLCH:
+		sin, cos := math.Sincos(v[2] * math.Pi / 180)
+		r, g, b := okLabToLinear([3]float64{v[0], v[1] * cos, v[1] * sin})
+		return fcolor{r: linearToSRGB(r), g: linearToSRGB(g), b: linearToSRGB(b)}
+	case InterpolationHSL:
+		r, g, b := hslToRGB(v)
+		return fcolor{r: r, g: g, b: b}
+	}
+	return fcolor{r: v[0], g: v[1], b: v[2]}
+}
+
+func srgbToLinear(c float64) float64 {
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+func linearToSRGB(c float64) float64 {
+	if c <= 0.0031308 {
+		return c * 12.92
+	}
+	return 1.055*math.Pow(c, 1/2.4) - 0.055
+}
+
+// linearToOKLab converts linear sRGB to OKLab, see
+// https://bottosson.github.io/posts/oklab/
+func linearToOKLab(r, g, b float64) [3]float64 {
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+	return [3]float64{
+		0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+	}
+}
+
+// okLabToLinear converts OKLab to linear sRGB.
+func okLabToLinear(lab [3]float64) (r, g, b float64) {
+	l := lab[0] + 0.3963377774*lab[1] + 0.2158037573*lab[2]
+	m := lab[0] - 0.1055613458*lab[1] - 0.0638541728*lab[2]
+	s := lab[0] - 0.0894841775*lab[1] - 1.2914855480*lab[2]
+	l, m, s = l*l*l, m*m*m, s*s*s
+	r = 4.0767416621*l - 3.3077115913*m + 0.2309699292*s
+	g = -1.2684380046*l + 2.6097574011*m - 0.3413193965*s
+	b = -0.0041960863*l - 0.7034186147*m + 1.7076147010*s
+	return
+}
+
+// rgbToHSL converts sRGB to hue in degrees, saturation and lightness.
+func rgbToHSL(r, g, b float64) [3]float64 {
+	hi, lo := max(r, g, b), min(r, g, b)
+	l := (hi + lo) / 2
+	d := hi - lo
+	if d == 0 {
+		return [3]float64{math.NaN(), 0, l}
+	}
+	s := d / (1 - math.Abs(2*l-1))
+	var h float64
+	switch hi {
+	case r:
+		h = math.Mod((g-b)/d+6, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	return [3]float64{h * 60, s, l}
+}
+
+// hslToRGB converts hue in degrees, saturation and lightness to sRGB.
+func hslToRGB(v [3]float64) (r, g, b float64) {
+	f := func(n float64) float64 {
+		k := math.Mod(n+v[0]/30, 12)
+		a := v[1] * min(v[2], 1-v[2])
+		return v[2] - a*max(-1, min(k-3, 9-k, 1))
+	}
+	return f(0), f(8), f(4)
+}
@@ -125,3 +125,27 @@ func (a Matrix) Rotate(angle float64) Matrix {
 func (a Matrix) Shear(x, y float64) Matrix {
 	return Shear(x, y).Multiply(a)
 }
+
+// Determinant returns the determinant of the linear part of the matrix.
+//
+// Determinant 返回矩阵线性部分的行列式。
+func (a Matrix) Determinant() float64 {
+	return a.XX*a.YY - a.XY*a.YX
+}
+
+// Invert returns the inverse matrix. ok is false if the matrix is singular,
+// in which case the identity matrix is returned.
+//
+// Invert 返回逆矩阵。若矩阵不可逆，ok 为 false 并返回单位矩阵。
+func (a Matrix) Invert() (m Matrix, ok bool) {
+	det := a.Determinant()
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return Identity(), false
+	}
+	inv := 1 / det
+	return Matrix{
+		a.YY * inv, -a.YX * inv,
+		-a.XY * inv, a.XX * inv,
+		(a.XY*a.Y0 - a.YY*a.X0) * inv, (a.YX*a.X0 - a.XX*a.Y0) * inv,
+	}, true
+}
@@ -0,0 +1,319 @@
+package gg
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// premultiplied is a premultiplied color with float components in [0, 1].
+type premultiplied [4]float64
+
+func toPremultiplied(c color.Color) premultiplied {
+	r, g, b, a := c.RGBA()
+	return premultiplied{float64(r) / 0xffff, float64(g) / 0xffff, float64(b) / 0xffff, float64(a) / 0xffff}
+}
+
+func (c premultiplied) scale(f float64) premultiplied {
+	return premultiplied{c[0] * f, c[1] * f, c[2] * f, c[3] * f}
+}
+
+func (c premultiplied) add(d premultiplied) premultiplied {
+	return premultiplied{c[0] + d[0], c[1] + d[1], c[2] + d[2], c[3] + d[3]}
+}
+
+func (c premultiplied) rgba() color.RGBA {
+	q := func(v float64) uint8 {
+		return uint8(max(0, min(255, math.Floor(v*255+0.5))))
+	}
+	a := q(c[3])
+	return color.RGBA{min(q(c[0]), a), min(q(c[1]), a), min(q(c[2]), a), a}
+}
+
+// bilinear interpolates the colors at the corners of the unit square in the
+// order (0, 0), (1, 0), (1, 1), (0, 1).
+func bilinear(c [4]premultiplied, u, v float64) premultiplied {
+	return c[0].scale((1 - u) * (1 - v)).
+		add(c[1].scale(u * (1 - v))).
+		add(c[2].scale(u * v)).
+		add(c[3].scale((1 - u) * v))
+}
+
+// Four Corner Gradient
+type fourCornerGradient struct {
+	x0, y0, x1, y1 float64
+	colors         [4]premultiplied
+}
+
+func (g *fourCornerGradient) ColorAt(x, y int) color.Color {
+	return g.colorAtF(float64(x), float64(y))
+}
+
+func (g *fourCornerGradient) colorAtF(x, y float64) color.Color {
+	u := max(0, min(1, (x+0.5-g.x0)/(g.x1-g.x0)))
+	v := max(0, min(1, (y+0.5-g.y0)/(g.y1-g.y0)))
+	if math.IsNaN(u) {
+		u = 0
+	}
+	if math.IsNaN(v) {
+		v = 0
+	}
+	return bilinear(g.colors, u, v).rgba()
+}
+
+// NewFourCornerGradient creates a gradient that blends bilinearly between
+// the colors of the corners of the rectangle from (x0, y0) to (x1, y1),
+// given in the order top-left, top-right, bottom-right and bottom-left.
+// Outside of the rectangle the colors of its edges are extended.
+//
+// NewFourCornerGradient 创建一个在 (x0, y0) 到 (x1, y1) 矩形四角颜色之间双线性混合的渐变，
+// 颜色依次为左上、右上、右下和左下。矩形之外沿用其边缘的颜色。
+func NewFourCornerGradient(x0, y0, x1, y1 float64, tl, tr, br, bl color.Color) Pattern {
+	return &fourCornerGradient{
+		x0: x0, y0: y0, x1: x1, y1: y1,
+		colors: [4]premultiplied{toPremultiplied(tl), toPremultiplied(tr), toPremultiplied(br), toPremultiplied(bl)},
+	}
+}
+
+// FreeformGradient blends the colors of a set of points by inverse distance
+// weighting, so that every pixel takes mostly the color of the nearest
+// points.
+//
+// FreeformGradient 通过反距离加权混合一组点的颜色，使每个像素主要呈现距离最近的点的颜色。
+type FreeformGradient struct {
+	points []Point
+	colors []premultiplied
+	power  float64
+}
+
+// NewFreeformGradient creates an empty freeform gradient. power controls
+// how quickly the influence of a point falls off with distance, 2 is a
+// good default.
+//
+// NewFreeformGradient 创建一个空的自由渐变。power 控制点的影响随距离衰减的速度，2 是合适的默认值。
+func NewFreeformGradient(power float64) *FreeformGradient {
+	return &FreeformGradient{power: power}
+}
+
+// AddColorPoint adds a point with the given color to the gradient.
+//
+// AddColorPoint 向渐变添加一个指定颜色的点。
+func (g *FreeformGradient) AddColorPoint(x, y float64, c color.Color) {
+	g.points = append(g.points, Point{x, y})
+	g.colors = append(g.colors, toPremultiplied(c))
+}
+
+// ColorAt satisfies the Pattern interface.
+func (g *FreeformGradient) ColorAt(x, y int) color.Color {
+	return g.colorAtF(float64(x), float64(y))
+}
+
+func (g *FreeformGradient) colorAtF(x, y float64) color.Color {
+	if len(g.points) == 0 {
+		return color.Transparent
+	}
+	p := Point{x + 0.5, y + 0.5}
+	var sum premultiplied
+	var total float64
+	for i, q := range g.points {
+		d := p.Distance(q)
+		if d == 0 {
+			return g.colors[i].rgba()
+		}
+		w := math.Pow(d, -g.power)
+		sum = sum.add(g.colors[i].scale(w))
+		total += w
+	}
+	return sum.scale(1 / total).rgba()
+}
+
+// meshPatch is a Coons patch with its boundary points and corner colors.
+type meshPatch struct {
+	points [12]Point
+	colors [4]premultiplied
+}
+
+// at returns the point of the patch at the parameters (u, v).
+func (p *meshPatch) at(u, v float64) Point {
+	pt := &p.points
+	bezier := func(p0, p1, p2, p3 Point, t float64) Point {
+		s := 1 - t
+		a, b, c, d := s*s*s, 3*s*s*t, 3*s*t*t, t*t*t
+		return Point{a*p0.X + b*p1.X + c*p2.X + d*p3.X, a*p0.Y + b*p1.Y + c*p2.Y + d*p3.Y}
+	}
+	top := bezier(pt[0], pt[1], pt[2], pt[3], u)
+	bottom := bezier(pt[9], pt[8], pt[7], pt[6], u)
+	left := bezier(pt[0], pt[11], pt[10], pt[9], v)
+	right := bezier(pt[3], pt[4], pt[5], pt[6], v)
+	w00, w10, w11, w01 := (1-u)*(1-v), u*(1-v), u*v, (1-u)*v
+	return Point{
+		(1-v)*top.X + v*bottom.X + (1-u)*left.X + u*right.X -
+			(w00*pt[0].X + w10*pt[3].X + w11*pt[6].X + w01*pt[9].X),
+		(1-v)*top.Y + v*bottom.Y + (1-u)*left.Y + u*right.Y -
+			(w00*pt[0].Y + w10*pt[3].Y + w11*pt[6].Y + w01*pt[9].Y),
+	}
+}
+
+// bounds returns the pixel bounds of the control points, which contain
+// the patch.
+func (p *meshPatch) bounds() image.Rectangle {
+	var b image.Rectangle
+	for _, q := range p.points {
+		b = b.Union(image.Rect(int(math.Floor(q.X)), int(math.Floor(q.Y)), int(math.Ceil(q.X))+1, int(math.Ceil(q.Y))+1))
+	}
+	return b
+}
+
+// MeshGradient is a gradient made of Coons patches, like PDF shading type 6.
+// Every patch is bounded by four cubic Bézier curves and blends the colors
+// of its corners. Patches added later are composited over earlier ones,
+// which show through translucent patches.
+//
+// MeshGradient 是由 Coons 曲面片组成的渐变，与 PDF 的 6 型着色相同。
+// 每个曲面片由四条三次贝塞尔曲线围成，并混合其四角的颜色。后添加的曲面片合成在先添加的之上，
+// 透过半透明的曲面片可以看到下方的曲面片。
+type MeshGradient struct {
+	mu      sync.Mutex // guards patches and rendering
+	patches []meshPatch
+	im      atomic.Pointer[image.RGBA] // the rendered patches, nil if outdated
+}
+
+// NewMeshGradient creates an empty mesh gradient.
+//
+// NewMeshGradient 创建一个空的网格渐变。
+func NewMeshGradient() *MeshGradient {
+	return &MeshGradient{}
+}
+
+// AddPatch adds a Coons patch. points lists the 12 boundary points
+// clockwise from the top-left corner: each side contributes its starting
+// corner followed by two control points, so the corners are points[0],
+// points[3], points[6] and points[9]. colors holds the colors of these
+// corners in the same order.
+//
+// AddPatch 添加一个 Coons 曲面片。points 从左上角开始按顺时针列出 12 个边界点：
+// 每条边依次给出起点角和两个控制点，因此角点为 points[0]、points[3]、points[6] 和 points[9]。
+// colors 按相同顺序给出这些角的颜色。
+func (g *MeshGradient) AddPatch(points [12]Point, colors [4]color.Color) {
+	p := meshPatch{points: points}
+	for i, c := range colors {
+		p.colors[i] = toPremultiplied(c)
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.patches = append(g.patches, p)
+	g.im.Store(nil)
+}
+
+// AddQuad adds a patch with straight sides between the four corners, given
+// clockwise from the top-left one.
+//
+// AddQuad 添加一个四边为直线的曲面片，四个角从左上角开始按顺时针给出。
+func (g *MeshGradient) AddQuad(corners [4]Point, colors [4]color.Color) {
+	var points [12]Point
+	for i, c := range corners {
+		next := corners[(i+1)%4]
+		points[3*i] = c
+		points[3*i+1] = c.Interpolate(next, 1.0/3)
+		points[3*i+2] = c.Interpolate(next, 2.0/3)
+	}
+	g.AddPatch(points, colors)
+}
+
+// ColorAt satisfies the Pattern interface.
+func (g *MeshGradient) ColorAt(x, y int) color.Color {
+	return g.image().RGBAAt(x, y)
+}
+
+func (g *MeshGradient) colorAtF(x, y float64) color.Color {
+	im := g.image()
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := x-x0, y-y0
+	ix, iy := int(x0), int(y0)
+	at := func(x, y int) premultiplied { return toPremultiplied(im.RGBAAt(x, y)) }
+	return bilinear([4]premultiplied{at(ix, iy), at(ix+1, iy), at(ix+1, iy+1), at(ix, iy+1)}, fx, fy).rgba()
+}
+
+// image returns the rendered patches, rendering them first if patches
+// were added since. It is safe to call concurrently, also with AddPatch.
+func (g *MeshGradient) image() *image.RGBA {
+	if im := g.im.Load(); im != nil {
+		return im
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	im := g.im.Load()
+	if im == nil {
+		im = g.render()
+		g.im.Store(im)
+	}
+	return im
+}
+
+// render rasterizes all patches by subdividing them into Gouraud-shaded
+// triangles. Every patch is rendered on its own and then composited over
+// the earlier ones, so translucent patches blend with what lies below.
+func (g *MeshGradient) render() *image.RGBA {
+	var b image.Rectangle
+	for i := range g.patches {
+		b = b.Union(g.patches[i].bounds())
+	}
+	im := image.NewRGBA(b)
+	for i := range g.patches {
+		p := &g.patches[i]
+		pb := p.bounds()
+		layer := image.NewRGBA(pb)
+		// about two pixels per subdivision
+		n := max(4, min(256, max(pb.Dx(), pb.Dy())/2))
+		grid := make([]Point, (n+1)*(n+1))
+		colors := make([]premultiplied, len(grid))
+		for y := 0; y <= n; y++ {
+			for x := 0; x <= n; x++ {
+				u, v := float64(x)/float64(n), float64(y)/float64(n)
+				grid[y*(n+1)+x] = p.at(u, v)
+				colors[y*(n+1)+x] = bilinear(p.colors, u, v)
+			}
+		}
+		for y := range n {
+			for x := range n {
+				k0 := y*(n+1) + x
+				k1, k2, k3 := k0+1, k0+n+2, k0+n+1
+				fillTriangle(layer, grid[k0], grid[k1], grid[k2], colors[k0], colors[k1], colors[k2])
+				fillTriangle(layer, grid[k0], grid[k2], grid[k3], colors[k0], colors[k2], colors[k3])
+			}
+		}
+		draw.Draw(im, pb, layer, pb.Min, draw.Over)
+	}
+	return im
+}
+
+// fillTriangle sets the pixels of im whose centers lie within the triangle
+// (p0, p1, p2), interpolating the colors of its vertices.
+func fillTriangle(im *image.RGBA, p0, p1, p2 Point, c0, c1, c2 premultiplied) {
+	area := (p1.X-p0.X)*(p2.Y-p0.Y) - (p2.X-p0.X)*(p1.Y-p0.Y)
+	if area == 0 {
+		return
+	}
+	r := image.Rect(
+		int(math.Floor(min(p0.X, p1.X, p2.X))), int(math.Floor(min(p0.Y, p1.Y, p2.Y))),
+		int(math.Ceil(max(p0.X, p1.X, p2.X))), int(math.Ceil(max(p0.Y, p1.Y, p2.Y))),
+	).Intersect(im.Rect)
+	// a small tolerance keeps shared edges free of gaps
+	const eps = 1e-9
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		py := float64(y) + 0.5
+		for x := r.Min.X; x < r.Max.X; x++ {
+			px := float64(x) + 0.5
+			w1 := ((px-p0.X)*(p2.Y-p0.Y) - (p2.X-p0.X)*(py-p0.Y)) / area
+			w2 := ((p1.X-p0.X)*(py-p0.Y) - (px-p0.X)*(p1.Y-p0.Y)) / area
+			w0 := 1 - w1 - w2
+			if w0 < -eps || w1 < -eps || w2 < -eps {
+				continue
+			}
+			im.SetRGBA(x, y, c0.scale(w0).add(c1.scale(w1)).add(c2.scale(w2)).rgba())
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package gg
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestFourCornerGradient(t *testing.T) {
+	red, green, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 255, 0, 255}, color.RGBA{0, 0, 255, 255}
+	g := NewFourCornerGradient(0, 0, 99, 99, red, green, blue, color.White)
+	tests := []struct {
+		x, y int
+		want color.Color
+	}{
+		{-10, -10, red},
+		{150, -10, green},
+		{150, 150, blue},
+		{-10, 150, color.White},
+		{49, 49, color.RGBA{128, 128, 128, 255}},
+		{49, -10, color.RGBA{128, 128, 0, 255}},
+	}
+	for _, tt := range tests {
+		if c := g.ColorAt(tt.x, tt.y); !sameColor(c, tt.want) {
+			t.Errorf("(%d, %d): got %v, want %v", tt.x, tt.y, c, tt.want)
+		}
+	}
+}
+
+func TestFreeformGradient(t *testing.T) {
+	g := NewFreeformGradient(2)
+	if c := g.ColorAt(0, 0); c != color.Transparent {
+		t.Errorf("empty gradient: got %v", c)
+	}
+	g.AddColorPoint(10.5, 10.5, color.Black)
+	g.AddColorPoint(90.5, 10.5, color.White)
+	if c := g.ColorAt(10, 10); !sameColor(c, color.Black) {
+		t.Errorf("got %v at the black point", c)
+	}
+	if c := g.ColorAt(50, 10); !sameColor(c, color.RGBA{128, 128, 128, 255}) {
+		t.Errorf("got %v halfway", c)
+	}
+	if r, _, _, _ := g.ColorAt(20, 10).RGBA(); r > 0x2000 {
+		t.Errorf("got red %#x near the black point", r)
+	}
+}
+
+func TestMeshGradient(t *testing.T) {
+	red, green, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 255, 0, 255}, color.RGBA{0, 0, 255, 255}
+	colors := [4]color.Color{red, green, blue, color.White}
+	m := NewMeshGradient()
+	m.AddQuad([4]Point{{0, 0}, {100, 0}, {100, 100}, {0, 100}}, colors)
+	// a flat quad matches the four corner gradient
+	g := NewFourCornerGradient(0, 0, 100, 100, red, green, blue, color.White)
+	for y := 0; y < 100; y += 9 {
+		for x := 0; x < 100; x += 9 {
+			if a, b := m.ColorAt(x, y), g.ColorAt(x, y); !sameColor(a, b) {
+				t.Fatalf("(%d, %d): got %v, want %v", x, y, a, b)
+			}
+		}
+	}
+	if c := m.ColorAt(100, 50); c != (color.RGBA{}) {
+		t.Errorf("got %v outside of the mesh", c)
+	}
+
+	// a curved patch fills its bulging sides without gaps
+	dc := NewContext(120, 120)
+	m = NewMeshGradient()
+	m.AddPatch([12]Point{
+		{10, 10}, {40, 0}, {80, 0}, {110, 10},
+		{120, 40}, {120, 80}, {110, 110},
+		{80, 120}, {40, 120}, {10, 110},
+		{0, 80}, {0, 40},
+	}, colors)
+	dc.SetFillStyle(m)
+	dc.DrawRectangle(0, 0, 120, 120)
+	dc.Fill()
+	if err := saveImage(dc, "TestMeshGradient"); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range [][2]int{{60, 5}, {115, 60}, {60, 115}, {5, 60}, {60, 60}} {
+		if _, _, _, a := dc.Image().At(p[0], p[1]).RGBA(); a != 0xffff {
+			t.Errorf("(%d, %d) is not covered", p[0], p[1])
+		}
+	}
+	for y := 12; y < 108; y++ {
+		for x := 12; x < 108; x++ {
+			if _, _, _, a := dc.Image().At(x, y).RGBA(); a != 0xffff {
+				t.Fatalf("gap at (%d, %d)", x, y)
+			}
+		}
+	}
+}
+
+func TestMeshGradientOverlap(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.NRGBA{0, 0, 255, 128}
+	m := NewMeshGradient()
+	m.AddQuad([4]Point{{0, 0}, {20, 0}, {20, 20}, {0, 20}}, [4]color.Color{red, red, red, red})
+	if c := m.ColorAt(15, 15); c != red {
+		t.Fatalf("got %v, want %v", c, red)
+	}
+	// a translucent patch added later blends with the one below
+	m.AddQuad([4]Point{{10, 10}, {30, 10}, {30, 30}, {10, 30}}, [4]color.Color{blue, blue, blue, blue})
+	for _, tc := range []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{5, 5, red},
+		{15, 15, color.RGBA{127, 0, 128, 255}},
+		{25, 25, color.RGBA{0, 0, 128, 128}},
+	} {
+		if c := m.ColorAt(tc.x, tc.y); !sameColor(c, tc.want) {
+			t.Errorf("(%d, %d): got %v, want %v", tc.x, tc.y, c, tc.want)
+		}
+	}
+}
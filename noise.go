@@ -0,0 +1,271 @@
+package gg
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+)
+
+// Noise defines the kind of procedural noise generated by a noise pattern.
+//
+// Noise 定义噪声图案生成的程序化噪声类型。
+type Noise int
+
+// Noise kinds.
+//
+// 噪声类型。
+const (
+	NoisePerlin  Noise = iota // Perlin gradient noise. Perlin 梯度噪声。
+	NoiseSimplex              // Simplex noise, with fewer directional artifacts. 方向性瑕疵更少的单形噪声。
+	NoiseValue                // Interpolated random lattice values. 插值的随机格点值。
+	NoiseWorley               // Distance to the nearest random feature point, giving cells. 到最近随机特征点的距离，形成细胞状图案。
+)
+
+// Noise Pattern
+type noisePattern struct {
+	gradient
+	noise   Noise
+	scale   float64
+	octaves int
+	perm    [512]uint8
+}
+
+func (p *noisePattern) ColorAt(x, y int) color.Color {
+	return p.colorAtF(float64(x), float64(y))
+}
+
+func (p *noisePattern) colorAtF(x, y float64) color.Color {
+	return p.sample(p, x, y)
+}
+
+// FillSpan satisfies the SpanPattern interface.
+func (p *noisePattern) FillSpan(dst []color.RGBA64, x, y int) {
+	p.fillSpan(p, dst, x, y, false)
+}
+
+func (p *noisePattern) fillSpanDeep(dst []color.RGBA64, x, y int) {
+	p.fillSpan(p, dst, x, y, true)
+}
+
+func (p *noisePattern) fillPoints(dst []color.RGBA64, at func(i int) (x, y float64, ok bool), deep bool) {
+	p.samplePoints(p, dst, at, deep)
+}
+
+func (p *noisePattern) param(x, y float64) (float64, bool) {
+	return p.spread.apply(p.fbm((x+0.5)/p.scale, (y+0.5)/p.scale), 0, 1), true
+}
+
+// NewNoisePattern creates a pattern of procedural noise whose values in
+// [0, 1] are mapped to colors through the color stops of the returned
+// gradient. scale is the size of the noise features in pixels. With more
+// than one octave, finer layers of noise are added as fractal Brownian
+// motion. The same seed always gives the same pattern.
+//
+// NewNoisePattern 创建程序化噪声图案，其 [0, 1] 范围内的值通过返回的渐变的色标映射为颜色。
+// scale 为噪声特征的像素大小。octaves 大于 1 时，会以分形布朗运动的方式叠加更精细的噪声层。
+// 相同的 seed 总是生成相同的图案。
+func NewNoisePattern(noise Noise, scale float64, octaves int, seed int64) Gradient {
+	p := &noisePattern{noise: noise, scale: scale, octaves: max(octaves, 1)}
+	for i, v := range rand.New(rand.NewSource(seed)).Perm(256) {
+		p.perm[i], p.perm[i+256] = uint8(v), uint8(v)
+	}
+	return p
+}
+
+// fbm sums the octaves of the noise at (x, y), normalized to [0, 1].
+func (p *noisePattern) fbm(x, y float64) float64 {
+	var sum, total float64
+	amp := 1.0
+	for i := range p.octaves {
+		// offset the octaves so that they do not share lattice points
+		o := float64(i) * 17.31
+		sum += amp * p.value(x+o, y+o)
+		total += amp
+		amp /= 2
+		x, y = x*2, y*2
+	}
+	return max(0, min(1, sum/total))
+}
+
+// value returns the noise at (x, y) in [0, 1].
+func (p *noisePattern) value(x, y float64) float64 {
+	switch p.noise {
+	case NoiseSimplex:
+		return (p.simplex(x, y) + 1) / 2
+	case NoiseValue:
+		return p.lattice(x, y)
+	case NoiseWorley:
+		return p.worley(x, y)
+	}
+	return (p.perlin(x, y) + 1) / 2
+}
+
+// hash returns a pseudo random byte for the lattice point (i, j).
+func (p *noisePattern) hash(i, j int) uint8 {
+	return p.perm[int(p.perm[i&255])+j&255]
+}
+
+// grad returns the dot product of (x, y) with one of eight gradient
+// directions chosen by h.
+func grad(h uint8, x, y float64) float64 {
+	switch h & 7 {
+	case 0:
+		return x + y
+	case 1:
+		return -x + y
+	case 2:
+		return x - y
+	case 3:
+		return -x - y
+	case 4:
+		return x
+	case 5:
+		return -x
+	case 6:
+		return y
+	}
+	return -y
+}
+
+// fade is the quintic smoothstep of improved Perlin noise.
+func fade(t float64) float64 {
+	return t * t * t * (t*(t*6-15) + 10)
+}
+
+func mix(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// perlin returns improved Perlin noise at (x, y) in about [-1, 1].
+func (p *noisePattern) perlin(x, y float64) float64 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	i, j := int(x0), int(y0)
+	fx, fy := x-x0, y-y0
+	u, v := fade(fx), fade(fy)
+	n00 := grad(p.hash(i, j), fx, fy)
+	n10 := grad(p.hash(i+1, j), fx-1, fy)
+	n01 := grad(p.hash(i, j+1), fx, fy-1)
+	n11 := grad(p.hash(i+1, j+1), fx-1, fy-1)
+	// the extrema of 2D Perlin noise are ±√2/2
+	return mix(mix(n00, n10, u), mix(n01, n11, u), v) * math.Sqrt2
+}
+
+// simplex returns 2D simplex noise at (x, y) in about [-1, 1].
+func (p *noisePattern) simplex(x, y float64) float64 {
+	const (
+		f2 = 0.36602540378443865 // (√3 - 1) / 2
+		g2 = 0.21132486540518713 // (3 - √3) / 6
+	)
+	s := (x + y) * f2
+	i, j := math.Floor(x+s), math.Floor(y+s)
+	t := (i + j) * g2
+	x0, y0 := x-(i-t), y-(j-t)
+	var i1, j1 float64
+	if x0 > y0 {
+		i1 = 1
+	} else {
+		j1 = 1
+	}
+	x1, y1 := x0-i1+g2, y0-j1+g2
+	x2, y2 := x0-1+2*g2, y0-1+2*g2
+	ii, jj := int(i), int(j)
+	corner := func(h uint8, x, y float64) float64 {
+		t := 0.5 - x*x - y*y
+		if t < 0 {
+			return 0
+		}
+		t *= t
+		return t * t * grad(h, x, y)
+	}
+	n := corner(p.hash(ii, jj), x0, y0) +
+		corner(p.hash(ii+int(i1), jj+int(j1)), x1, y1) +
+		corner(p.hash(ii+1, jj+1), x2, y2)
+	return max(-1, min(1, 70*n))
+}
+
+// lattice returns value noise at (x, y) in [0, 1].
+func (p *noisePattern) lattice(x, y float64) float64 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	i, j := int(x0), int(y0)
+	u, v := fade(x-x0), fade(y-y0)
+	at := func(i, j int) float64 { return float64(p.hash(i, j)) / 255 }
+	return mix(mix(at(i, j), at(i+1, j), u), mix(at(i, j+1), at(i+1, j+1), u), v)
+}
+
+// worley returns the distance from (x, y) to the nearest feature point,
+// one of which lies in every unit cell, clamped to [0, 1].
+func (p *noisePattern) worley(x, y float64) float64 {
+	x0, y0 := math.Floor(x), math.Floor(y)
+	i, j := int(x0), int(y0)
+	d := math.Inf(1)
+	for dj := -1; dj <= 1; dj++ {
+		for di := -1; di <= 1; di++ {
+			h := p.hash(i+di, j+dj)
+			fx := x0 + float64(di) + float64(h)/255
+			fy := y0 + float64(dj) + float64(p.perm[h])/255
+			d = min(d, math.Hypot(x-fx, y-fy))
+		}
+	}
+	return min(d, 1)
+}
+
+// Checkerboard Pattern
+type checkerboardPattern struct {
+	size   float64
+	c0, c1 color.Color
+}
+
+func (p *checkerboardPattern) ColorAt(x, y int) color.Color {
+	return p.colorAtF(float64(x), float64(y))
+}
+
+func (p *checkerboardPattern) colorAtF(x, y float64) color.Color {
+	i := int(math.Floor((x + 0.5) / p.size))
+	j := int(math.Floor((y + 0.5) / p.size))
+	if (i+j)&1 == 0 {
+		return p.c0
+	}
+	return p.c1
+}
+
+// NewCheckerboardPattern creates a checkerboard of squares with the given
+// size, starting with c0 at the origin.
+//
+// NewCheckerboardPattern 创建由指定大小方格组成的棋盘格图案，原点处为 c0。
+func NewCheckerboardPattern(size float64, c0, c1 color.Color) Pattern {
+	return &checkerboardPattern{size: size, c0: c0, c1: c1}
+}
+
+// Stripe Pattern
+type stripePattern struct {
+	width    float64
+	sin, cos float64
+	colors   []color.Color
+}
+
+func (p *stripePattern) ColorAt(x, y int) color.Color {
+	return p.colorAtF(float64(x), float64(y))
+}
+
+func (p *stripePattern) colorAtF(x, y float64) color.Color {
+	if len(p.colors) == 0 {
+		return color.Transparent
+	}
+	d := ((x+0.5)*p.cos + (y+0.5)*p.sin) / p.width
+	i := int(math.Floor(d)) % len(p.colors)
+	if i < 0 {
+		i += len(p.colors)
+	}
+	return p.colors[i]
+}
+
+// NewStripePattern creates parallel stripes of the given width cycling
+// through colors. The stripes run perpendicular to the direction given by
+// angle in radians, so 0 gives vertical stripes.
+//
+// NewStripePattern 创建按给定宽度、依次循环使用各颜色的平行条纹。
+// 条纹垂直于弧度为 angle 的方向，因此 0 为竖直条纹。
+func NewStripePattern(width, angle float64, colors ...color.Color) Pattern {
+	sin, cos := math.Sincos(angle)
+	return &stripePattern{width: width, sin: sin, cos: cos, colors: colors}
+}
@@ -0,0 +1,78 @@
+package gg
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestNoisePattern(t *testing.T) {
+	for _, noise := range []Noise{NoisePerlin, NoiseSimplex, NoiseValue, NoiseWorley} {
+		a := NewNoisePattern(noise, 16, 3, 1).(*noisePattern)
+		b := NewNoisePattern(noise, 16, 3, 1).(*noisePattern)
+		c := NewNoisePattern(noise, 16, 3, 2).(*noisePattern)
+		lo, hi := 1.0, 0.0
+		differ := false
+		for y := -100; y < 100; y += 3 {
+			for x := -100; x < 100; x += 3 {
+				v := a.fbm(float64(x)/16, float64(y)/16)
+				if v != b.fbm(float64(x)/16, float64(y)/16) {
+					t.Fatalf("noise %d is not deterministic", noise)
+				}
+				differ = differ || v != c.fbm(float64(x)/16, float64(y)/16)
+				lo, hi = min(lo, v), max(hi, v)
+			}
+		}
+		if !differ {
+			t.Errorf("noise %d does not depend on the seed", noise)
+		}
+		// the noise should use most of its range
+		if lo > 0.3 || hi < 0.7 {
+			t.Errorf("noise %d spans only [%.2f, %.2f]", noise, lo, hi)
+		}
+	}
+
+	g := NewNoisePattern(NoisePerlin, 16, 1, 1)
+	if c := g.ColorAt(0, 0); c != color.Transparent {
+		t.Errorf("got %v without color stops", c)
+	}
+	g.AddColorStop(0, color.Black)
+	g.AddColorStop(1, color.Black)
+	if c := g.ColorAt(5, 5); !sameColor(c, color.Black) {
+		t.Errorf("got %v, want black", c)
+	}
+}
+
+func TestCheckerboardPattern(t *testing.T) {
+	p := NewCheckerboardPattern(10, color.Black, color.White)
+	tests := []struct {
+		x, y int
+		want color.Color
+	}{
+		{0, 0, color.Black},
+		{9, 9, color.Black},
+		{10, 0, color.White},
+		{10, 10, color.Black},
+		{-1, 0, color.White},
+		{-1, -1, color.Black},
+	}
+	for _, tt := range tests {
+		if c := p.ColorAt(tt.x, tt.y); c != tt.want {
+			t.Errorf("(%d, %d): got %v, want %v", tt.x, tt.y, c, tt.want)
+		}
+	}
+}
+
+func TestStripePattern(t *testing.T) {
+	red, green, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 255, 0, 255}, color.RGBA{0, 0, 255, 255}
+	p := NewStripePattern(5, 0, red, green, blue)
+	for x, want := range map[int]color.Color{0: red, 4: red, 5: green, 14: blue, 15: red, -1: blue, -6: green} {
+		if c := p.ColorAt(x, 123); c != want {
+			t.Errorf("x = %d: got %v, want %v", x, c, want)
+		}
+	}
+	// horizontal stripes only depend on y
+	p = NewStripePattern(5, Radians(90), red, green)
+	if a, b := p.ColorAt(0, 7), p.ColorAt(100, 7); a != green || b != green {
+		t.Errorf("got %v and %v, want green", a, b)
+	}
+}
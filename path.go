@@ -166,11 +166,3 @@ func rasterPath(paths [][]Point) raster.Path {
 	}
 	return result
 }
-
-func dashed(path raster.Path, dashes []float64, offset float64) (raster.Path, error) {
-	fp, err := flattenPath(path)
-	if err != nil {
-		return nil, err
-	}
-	return rasterPath(dashPath(fp, dashes, offset)), nil
-}
@@ -3,8 +3,11 @@ package gg
 import (
 	"image"
 	"image/color"
+	"math"
+	"slices"
 
 	"github.com/golang/freetype/raster"
+	"golang.org/x/image/draw"
 )
 
 // RepeatOp defines how a surface pattern repeats.
@@ -29,6 +32,20 @@ type Pattern interface {
 	ColorAt(x, y int) color.Color
 }
 
+// SpanPattern is a Pattern that can also compute a whole row of pixels at
+// once, which is much faster than calling ColorAt for every pixel. dst must
+// be filled with the premultiplied colors of the pixels from (x, y) to
+// (x+len(dst)-1, y), exactly as returned by the RGBA method of the colors
+// from ColorAt.
+//
+// SpanPattern 是还能一次计算一整行像素的 Pattern，这比对每个像素调用 ColorAt 快得多。
+// dst 须填入从 (x, y) 到 (x+len(dst)-1, y) 各像素的预乘颜色，
+// 其值与 ColorAt 所返回颜色的 RGBA 方法的结果完全相同。
+type SpanPattern interface {
+	Pattern
+	FillSpan(dst []color.RGBA64, x, y int)
+}
+
 // Solid Pattern
 type solidPattern struct {
 	color color.Color
@@ -38,6 +55,14 @@ func (p *solidPattern) ColorAt(_, _ int) color.Color {
 	return p.color
 }
 
+// FillSpan satisfies the SpanPattern interface.
+func (p *solidPattern) FillSpan(dst []color.RGBA64, _, _ int) {
+	c := rgba64(p.color.RGBA())
+	for i := range dst {
+		dst[i] = c
+	}
+}
+
 // NewSolidPattern creates a pattern that always returns the given color.
 //
 // NewSolidPattern 创建一个始终返回指定颜色的图案。
@@ -45,44 +70,323 @@ func NewSolidPattern(color color.Color) Pattern {
 	return &solidPattern{color: color}
 }
 
+// SurfacePattern is a pattern that tiles an image.
+//
+// SurfacePattern 是平铺图像的图案。
+type SurfacePattern interface {
+	Pattern
+	// SetOrigin moves the top-left corner of the image to (x, y).
+	//
+	// SetOrigin 将图像的左上角移动到 (x, y)。
+	SetOrigin(x, y float64)
+	// SetScaleStyle sets how the image is sampled between its pixels, which
+	// matters for fractional origins and transformed patterns.
+	// NearestNeighbor, the default, keeps pixels sharp, BiLinear and
+	// ApproxBiLinear interpolate bilinearly and CatmullRom bicubically.
+	//
+	// SetScaleStyle 设置在图像像素之间采样的方式，这对小数原点和变换后的图案有影响。
+	// 默认的 NearestNeighbor 保持像素锐利，BiLinear 和 ApproxBiLinear 为双线性插值，
+	// CatmullRom 为双三次插值。
+	SetScaleStyle(style ScaleStyle)
+	// SetMirror makes every other repetition of the image mirrored, so that
+	// the tiles join seamlessly.
+	//
+	// SetMirror 使图像每隔一次重复就镜像一次，从而使图块无缝衔接。
+	SetMirror(mirror bool)
+}
+
 // Surface Pattern
 type surfacePattern struct {
-	im image.Image
-	op RepeatOp
+	im     image.Image
+	op     RepeatOp
+	ox, oy float64
+	style  ScaleStyle
+	mirror bool
+}
+
+func (p *surfacePattern) SetOrigin(x, y float64) {
+	p.ox, p.oy = x, y
+}
+
+func (p *surfacePattern) SetScaleStyle(style ScaleStyle) {
+	p.style = style
+}
+
+func (p *surfacePattern) SetMirror(mirror bool) {
+	p.mirror = mirror
 }
 
 func (p *surfacePattern) ColorAt(x, y int) color.Color {
+	return p.colorAtF(float64(x), float64(y))
+}
+
+func (p *surfacePattern) colorAtF(x, y float64) color.Color {
+	// position in image pixels, whose centers lie on integers
+	x, y = x-p.ox, y-p.oy
+	switch p.style {
+	case BiLinear, ApproxBiLinear:
+		x0, y0 := math.Floor(x), math.Floor(y)
+		i, j := int(x0), int(y0)
+		return bilinear([4]premultiplied{
+			p.texel(i, j), p.texel(i+1, j), p.texel(i+1, j+1), p.texel(i, j+1),
+		}, x-x0, y-y0).rgba()
+	case CatmullRom:
+		x0, y0 := math.Floor(x), math.Floor(y)
+		i, j := int(x0), int(y0)
+		wx, wy := catmullRom(x-x0), catmullRom(y-y0)
+		var c premultiplied
+		for dj := range 4 {
+			for di := range 4 {
+				c = c.add(p.texel(i+di-1, j+dj-1).scale(wx[di] * wy[dj]))
+			}
+		}
+		return c.rgba()
+	}
+	if k := p.style.kernel(); k != nil {
+		return p.kernelAt(k, x, y).rgba()
+	}
+	i, j := p.wrap(int(math.Floor(x+0.5)), int(math.Floor(y+0.5)))
+	if i < 0 {
+		return color.Transparent
+	}
 	b := p.im.Bounds()
-	switch p.op {
-	case RepeatX:
-		if y >= b.Dy() {
-			return color.Transparent
+	return p.im.At(i+b.Min.X, j+b.Min.Y)
+}
+
+// FillSpan satisfies the SpanPattern interface.
+func (p *surfacePattern) FillSpan(dst []color.RGBA64, x, y int) {
+	if p.style != NearestNeighbor {
+		for k := range dst {
+			dst[k] = rgba64(p.colorAtF(float64(x+k), float64(y)).RGBA())
 		}
-	case RepeatY:
-		if x >= b.Dx() {
-			return color.Transparent
+		return
+	}
+	b := p.im.Bounds()
+	rgba, _ := p.im.(*image.RGBA)
+	i0 := int(math.Floor(float64(x) - p.ox + 0.5))
+	j0 := int(math.Floor(float64(y) - p.oy + 0.5))
+	for k := range dst {
+		i, j := p.wrap(i0+k, j0)
+		switch {
+		case i < 0:
+			dst[k] = color.RGBA64{}
+		case rgba != nil:
+			c := rgba.RGBAAt(i+b.Min.X, j+b.Min.Y)
+			dst[k] = color.RGBA64{uint16(c.R) * 0x101, uint16(c.G) * 0x101, uint16(c.B) * 0x101, uint16(c.A) * 0x101}
+		default:
+			dst[k] = rgba64(p.im.At(i+b.Min.X, j+b.Min.Y).RGBA())
+		}
+	}
+}
+
+func (p *surfacePattern) fillPoints(dst []color.RGBA64, at func(i int) (x, y float64, ok bool), _ bool) {
+	for i := range dst {
+		x, y, ok := at(i)
+		if !ok {
+			dst[i] = color.RGBA64{}
+			continue
+		}
+		dst[i] = rgba64(p.colorAtF(x, y).RGBA())
+	}
+}
+
+// texel returns the premultiplied color of the image pixel (i, j) after
+// wrapping.
+func (p *surfacePattern) texel(i, j int) premultiplied {
+	i, j = p.wrap(i, j)
+	if i < 0 {
+		return premultiplied{}
+	}
+	b := p.im.Bounds()
+	if im, ok := p.im.(*image.RGBA); ok {
+		c := im.RGBAAt(i+b.Min.X, j+b.Min.Y)
+		return premultiplied{float64(c.R) / 255, float64(c.G) / 255, float64(c.B) / 255, float64(c.A) / 255}
+	}
+	return toPremultiplied(p.im.At(i+b.Min.X, j+b.Min.Y))
+}
+
+// wrap maps the pixel (i, j) into the image according to the repeat mode,
+// returning -1, -1 if it lies outside of a non-repeating direction.
+func (p *surfacePattern) wrap(i, j int) (int, int) {
+	b := p.im.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return -1, -1
+	}
+	repeatX := p.op == RepeatBoth || p.op == RepeatX
+	repeatY := p.op == RepeatBoth || p.op == RepeatY
+	if (!repeatX && (i < 0 || i >= w)) || (!repeatY && (j < 0 || j >= h)) {
+		return -1, -1
+	}
+	return wrapIndex(i, w, p.mirror), wrapIndex(j, h, p.mirror)
+}
+
+// wrapIndex maps i into [0, n), mirroring every other period if mirror is
+// set.
+func wrapIndex(i, n int, mirror bool) int {
+	if !mirror {
+		if i %= n; i < 0 {
+			i += n
+		}
+		return i
+	}
+	if i %= 2 * n; i < 0 {
+		i += 2 * n
+	}
+	if i >= n {
+		i = 2*n - 1 - i
+	}
+	return i
+}
+
+// catmullRom returns the weights of the four pixels around a position t
+// past the second one.
+func catmullRom(t float64) [4]float64 {
+	return [4]float64{
+		((-t+2)*t - 1) * t / 2,
+		((3*t-5)*t*t + 2) / 2,
+		((-3*t+4)*t + 1) * t / 2,
+		(t - 1) * t * t / 2,
+	}
+}
+
+// kernelAt returns the premultiplied color at (x, y) in image pixels,
+// weighting the pixels within the support of the kernel k.
+func (p *surfacePattern) kernelAt(k *draw.Kernel, x, y float64) premultiplied {
+	// the kernels have a support of at most 3 pixels
+	taps := func(v float64) (i0, n int, w [8]float64) {
+		i0 = int(math.Ceil(v - k.Support))
+		for i := i0; float64(i) <= v+k.Support && n < len(w); i++ {
+			w[n] = k.At(math.Abs(v - float64(i)))
+			n++
 		}
-	case RepeatNone:
-		if x >= b.Dx() || y >= b.Dy() {
-			return color.Transparent
+		return
+	}
+	i0, nx, wx := taps(x)
+	j0, ny, wy := taps(y)
+	var c premultiplied
+	var sum float64
+	for dj, ky := range wy[:ny] {
+		for di, kx := range wx[:nx] {
+			c = c.add(p.texel(i0+di, j0+dj).scale(kx * ky))
+			sum += kx * ky
 		}
 	}
-	x = x%b.Dx() + b.Min.X
-	y = y%b.Dy() + b.Min.Y
-	return p.im.At(x, y)
+	if sum == 0 {
+		return premultiplied{}
+	}
+	return c.scale(1 / sum)
 }
 
-// NewSurfacePattern creates a pattern from an image with the given repeat mode.
+// NewSurfacePattern creates a pattern from an image with the given repeat
+// mode. It samples the nearest pixel and is anchored at the origin until
+// configured otherwise.
 //
 // NewSurfacePattern 使用指定重复模式从图像创建图案。
-func NewSurfacePattern(im image.Image, op RepeatOp) Pattern {
-	return &surfacePattern{im: im, op: op}
+// 在另行设置之前，它采样最近的像素，并以原点为锚点。
+func NewSurfacePattern(im image.Image, op RepeatOp) SurfacePattern {
+	return &surfacePattern{im: im, op: op, style: NearestNeighbor}
+}
+
+// floatPattern is implemented by patterns that can be sampled between
+// pixels. colorAtF(float64(x), float64(y)) must equal ColorAt(x, y).
+type floatPattern interface {
+	colorAtF(x, y float64) color.Color
+}
+
+// colorAtF samples p at a fractional position, rounding to the nearest
+// pixel if p only supports integer coordinates.
+func colorAtF(p Pattern, x, y float64) color.Color {
+	if fp, ok := p.(floatPattern); ok {
+		return fp.colorAtF(x, y)
+	}
+	return p.ColorAt(int(math.Floor(x+0.5)), int(math.Floor(y+0.5)))
+}
+
+// pointSpanPattern is implemented by the patterns that can fill a span
+// with their colors at arbitrary points, which keeps the span fast path of
+// transformed patterns. The i-th color is that of colorAtF at the point
+// returned by at(i), or transparent if it reports false. deep is as for
+// deepSpanPattern.
+type pointSpanPattern interface {
+	fillPoints(dst []color.RGBA64, at func(i int) (x, y float64, ok bool), deep bool)
+}
+
+// fillPoints fills dst with the colors of p at the points returned by at,
+// through the span code of p if it has one.
+func fillPoints(p Pattern, dst []color.RGBA64, at func(i int) (x, y float64, ok bool), deep bool) {
+	if pp, ok := p.(pointSpanPattern); ok {
+		pp.fillPoints(dst, at, deep)
+		return
+	}
+	for i := range dst {
+		x, y, ok := at(i)
+		if !ok {
+			dst[i] = color.RGBA64{}
+			continue
+		}
+		dst[i] = rgba64(colorAtF(p, x, y).RGBA())
+	}
+}
+
+// Transformed Pattern
+type transformedPattern struct {
+	p       Pattern
+	m, inv  Matrix
+	invalid bool
+}
+
+func (p *transformedPattern) ColorAt(x, y int) color.Color {
+	if p.invalid {
+		return color.Transparent
+	}
+	// map the pixel center back into pattern space
+	px, py := p.inv.TransformPoint(float64(x)+0.5, float64(y)+0.5)
+	return colorAtF(p.p, px-0.5, py-0.5)
+}
+
+// FillSpan satisfies the SpanPattern interface.
+func (p *transformedPattern) FillSpan(dst []color.RGBA64, x, y int) {
+	p.fillSpan(dst, x, y, false)
+}
+
+func (p *transformedPattern) fillSpanDeep(dst []color.RGBA64, x, y int) {
+	p.fillSpan(dst, x, y, true)
+}
+
+func (p *transformedPattern) fillSpan(dst []color.RGBA64, x0, y int, deep bool) {
+	if p.invalid {
+		clear(dst)
+		return
+	}
+	fy := float64(y) + 0.5
+	fillPoints(p.p, dst, func(i int) (float64, float64, bool) {
+		px, py := p.inv.TransformPoint(float64(x0+i)+0.5, fy)
+		return px - 0.5, py - 0.5, true
+	}, deep)
+}
+
+// NewTransformedPattern creates a pattern that draws p transformed by m,
+// which maps pattern space to device space. Transforming an already
+// transformed pattern combines both matrices.
+//
+// NewTransformedPattern 创建一个将 p 按 m 变换后绘制的图案，m 将图案空间映射到设备空间。
+// 对已变换的图案再次变换时会合并两个矩阵。
+func NewTransformedPattern(p Pattern, m Matrix) Pattern {
+	if tp, ok := p.(*transformedPattern); ok {
+		p, m = tp.p, tp.m.Multiply(m)
+	}
+	inv, ok := m.Invert()
+	return &transformedPattern{p: p, m: m, inv: inv, invalid: !ok}
 }
 
 type patternPainter struct {
-	im   *image.RGBA
+	im   draw.Image
 	mask *image.Alpha
 	p    Pattern
+	sp   SpanPattern     // p if it implements SpanPattern
+	dp   deepSpanPattern // p if it implements deepSpanPattern and im is deep
+	row  []color.RGBA64
 }
 
 // Paint satisfies the Painter interface.
@@ -104,35 +408,76 @@ func (r *patternPainter) Paint(ss []raster.Span, _ bool) {
 		if s.X0 >= s.X1 {
 			continue
 		}
-		const m = 1<<16 - 1
-		y := s.Y - r.im.Rect.Min.Y
-		x0 := s.X0 - r.im.Rect.Min.X
-		// RGBAPainter.Paint() in $GOPATH/src/github.com/golang/freetype/raster/paint.go
-		i0 := (s.Y-r.im.Rect.Min.Y)*r.im.Stride + (s.X0-r.im.Rect.Min.X)*4
-		i1 := i0 + (s.X1-s.X0)*4
-		for i, x := i0, x0; i < i1; i, x = i+4, x+1 {
-			ma := s.Alpha
-			if r.mask != nil {
-				ma = ma * uint32(r.mask.AlphaAt(x, y).A) / 255
-				if ma == 0 {
-					continue
-				}
+		y := s.Y - b.Min.Y
+		x0 := s.X0 - b.Min.X
+		if r.sp != nil {
+			r.row = slices.Grow(r.row[:0], s.X1-s.X0)[:s.X1-s.X0]
+			if r.dp != nil {
+				r.dp.fillSpanDeep(r.row, x0, y)
+			} else {
+				r.sp.FillSpan(r.row, x0, y)
 			}
-			c := r.p.ColorAt(x, y)
-			cr, cg, cb, ca := c.RGBA()
-			dr := uint32(r.im.Pix[i+0])
-			dg := uint32(r.im.Pix[i+1])
-			db := uint32(r.im.Pix[i+2])
-			da := uint32(r.im.Pix[i+3])
-			a := (m - (ca * ma / m)) * 0x101
-			r.im.Pix[i+0] = uint8((dr*a + cr*ma) / m >> 8)
-			r.im.Pix[i+1] = uint8((dg*a + cg*ma) / m >> 8)
-			r.im.Pix[i+2] = uint8((db*a + cb*ma) / m >> 8)
-			r.im.Pix[i+3] = uint8((da*a + ca*ma) / m >> 8)
+		}
+		switch im := r.im.(type) {
+		case *image.RGBA:
+			r.paintRGBA(im, s, x0, y)
+		case *image.RGBA64:
+			r.paintRGBA64(im, s, x0, y)
+		case *RGBAF32:
+			r.paintRGBAF32(im, s, x0, y)
+		default:
+			r.paintImage(im, s, x0, y)
 		}
 	}
 }
 
-func newPatternPainter(im *image.RGBA, mask *image.Alpha, p Pattern) *patternPainter {
-	return &patternPainter{im, mask, p}
+// coverage returns the alpha of the span at the pixel (x, y) after
+// applying the mask.
+func (r *patternPainter) coverage(alpha uint32, x, y int) uint32 {
+	if r.mask != nil {
+		return alpha * uint32(r.mask.AlphaAt(x, y).A) / 255
+	}
+	return alpha
+}
+
+// color returns the premultiplied color of the pattern at the pixel
+// (x, y) of the span starting at x0.
+func (r *patternPainter) color(x, x0, y int) (uint32, uint32, uint32, uint32) {
+	if r.sp != nil {
+		c := r.row[x-x0]
+		return uint32(c.R), uint32(c.G), uint32(c.B), uint32(c.A)
+	}
+	return r.p.ColorAt(x, y).RGBA()
+}
+
+func (r *patternPainter) paintRGBA(im *image.RGBA, s raster.Span, x0, y int) {
+	const m = 1<<16 - 1
+	// RGBAPainter.Paint() in $GOPATH/src/github.com/golang/freetype/raster/paint.go
+	i0 := y*im.Stride + x0*4
+	i1 := i0 + (s.X1-s.X0)*4
+	for i, x := i0, x0; i < i1; i, x = i+4, x+1 {
+		ma := r.coverage(s.Alpha, x, y)
+		if ma == 0 {
+			continue
+		}
+		cr, cg, cb, ca := r.color(x, x0, y)
+		dr := uint32(im.Pix[i+0])
+		dg := uint32(im.Pix[i+1])
+		db := uint32(im.Pix[i+2])
+		da := uint32(im.Pix[i+3])
+		a := (m - (ca * ma / m)) * 0x101
+		im.Pix[i+0] = uint8((dr*a + cr*ma) / m >> 8)
+		im.Pix[i+1] = uint8((dg*a + cg*ma) / m >> 8)
+		im.Pix[i+2] = uint8((db*a + cb*ma) / m >> 8)
+		im.Pix[i+3] = uint8((da*a + ca*ma) / m >> 8)
+	}
+}
+
+func newPatternPainter(im draw.Image, mask *image.Alpha, p Pattern) *patternPainter {
+	sp, _ := p.(SpanPattern)
+	var dp deepSpanPattern
+	if _, ok := im.(*image.RGBA); !ok {
+		dp, _ = p.(deepSpanPattern)
+	}
+	return &patternPainter{im: im, mask: mask, p: p, sp: sp, dp: dp}
 }
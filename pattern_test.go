@@ -0,0 +1,188 @@
+package gg
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestTransformedPatternIdentity(t *testing.T) {
+	g := NewRadialGradient(30, 40, 5, 50, 50, 40)
+	g.AddColorStop(0, color.Black)
+	g.AddColorStop(1, color.White)
+	patterns := []Pattern{g, NewSurfacePattern(gridImage(), RepeatBoth)}
+	for i, p := range patterns {
+		tp := NewTransformedPattern(NewTransformedPattern(p, Translate(7, -3)), Translate(-7, 3))
+		for y := -10; y < 110; y += 7 {
+			for x := -10; x < 110; x += 7 {
+				if a, b := p.ColorAt(x, y), tp.ColorAt(x, y); a != b {
+					t.Fatalf("pattern %d at (%d, %d): got %v, want %v", i, x, y, b, a)
+				}
+			}
+		}
+	}
+}
+
+func TestSurfacePatternNegative(t *testing.T) {
+	im := image.NewRGBA(image.Rect(10, 10, 14, 13))
+	im.Set(13, 12, color.White)
+	p := NewSurfacePattern(im, RepeatBoth)
+	if c := p.ColorAt(-1, -1); c != color.RGBAModel.Convert(color.White) {
+		t.Errorf("got %v at (-1, -1)", c)
+	}
+	if c := NewSurfacePattern(im, RepeatNone).ColorAt(-1, -1); c != color.Transparent {
+		t.Errorf("got %v at (-1, -1) without repeat", c)
+	}
+}
+
+func TestFillStyleFollowsMatrix(t *testing.T) {
+	draw := func(transform bool) *Context {
+		dc := NewContext(100, 100)
+		var g Gradient
+		if transform {
+			dc.Translate(20, 10)
+			dc.Scale(2, 2)
+			g = NewRadialGradient(0, 0, 0, 0, 0, 30)
+		} else {
+			g = NewRadialGradient(20, 10, 0, 20, 10, 60)
+		}
+		g.AddColorStop(0, color.Black)
+		g.AddColorStop(1, color.White)
+		dc.SetFillStyle(g)
+		dc.Identity()
+		dc.DrawRectangle(0, 0, 100, 100)
+		dc.Fill()
+		return dc
+	}
+	a, b := draw(false), draw(true)
+	if err := saveImage(b, "TestFillStyleFollowsMatrix"); err != nil {
+		t.Fatal(err)
+	}
+	for y := range 100 {
+		for x := range 100 {
+			if !sameColor(a.Image().At(x, y), b.Image().At(x, y)) {
+				t.Fatalf("(%d, %d): got %v, want %v", x, y, b.Image().At(x, y), a.Image().At(x, y))
+			}
+		}
+	}
+}
+
+func TestSurfacePatternOptions(t *testing.T) {
+	im := image.NewRGBA(image.Rect(5, 5, 8, 7))
+	for y := 5; y < 7; y++ {
+		for x := 5; x < 8; x++ {
+			v := uint8(40*(x-5) + 120*(y-5))
+			im.SetRGBA(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+	at := func(p Pattern, x, y int) uint8 {
+		return color.RGBAModel.Convert(p.ColorAt(x, y)).(color.RGBA).R
+	}
+
+	// filtering does not change the pixels themselves
+	nearest := NewSurfacePattern(im, RepeatBoth)
+	for _, style := range []ScaleStyle{BiLinear, ApproxBiLinear, CatmullRom} {
+		p := NewSurfacePattern(im, RepeatBoth)
+		p.SetScaleStyle(style)
+		for y := -4; y < 4; y++ {
+			for x := -4; x < 4; x++ {
+				if a, b := at(p, x, y), at(nearest, x, y); a != b {
+					t.Fatalf("style %d at (%d, %d): got %d, want %d", style, x, y, a, b)
+				}
+			}
+		}
+	}
+
+	// a half pixel origin blends neighbouring pixels
+	p := NewSurfacePattern(im, RepeatNone)
+	p.SetScaleStyle(BiLinear)
+	p.SetOrigin(10.5, 20)
+	if v := at(p, 11, 20); v != 20 {
+		t.Errorf("got %d between the first two pixels, want 20", v)
+	}
+	if c := p.ColorAt(0, 0); c != (color.RGBA{}) {
+		t.Errorf("got %v outside of the image", c)
+	}
+
+	p = NewSurfacePattern(im, RepeatBoth)
+	p.SetOrigin(10, 20)
+	if v := at(p, 11, 21); v != 160 {
+		t.Errorf("got %d with an origin, want 160", v)
+	}
+
+	// mirrored tiles repeat every two images
+	p = NewSurfacePattern(im, RepeatX)
+	p.SetMirror(true)
+	for x, want := range map[int]uint8{0: 0, 2: 80, 3: 80, 5: 0, 6: 0, -1: 0, -3: 80, -4: 80} {
+		if v := at(p, x, 0); v != want {
+			t.Errorf("mirror at x = %d: got %d, want %d", x, v, want)
+		}
+	}
+	if c := p.ColorAt(0, 2); c != color.Transparent {
+		t.Errorf("got %v below the image", c)
+	}
+}
+
+func TestSpanPattern(t *testing.T) {
+	stops := func(g Gradient) Gradient {
+		g.AddColorStop(0, color.RGBA{255, 0, 0, 255})
+		g.AddColorStop(0.4, color.NRGBA{0, 255, 0, 128})
+		g.AddColorStop(1, color.RGBA{0, 0, 255, 255})
+		return g
+	}
+	linear := stops(NewLinearGradient(10, 20, 90, 70))
+	linear.SetSpread(SpreadReflect)
+	oklab := stops(NewRadialGradient(50, 50, 0, 50, 50, 40))
+	oklab.SetInterpolation(InterpolationOKLab)
+	oklab.SetDither(true)
+	mirror := NewSurfacePattern(gridImage(), RepeatX)
+	mirror.SetMirror(true)
+	mirror.SetOrigin(3.7, -2.2)
+	bilinear := NewSurfacePattern(image.NewNRGBA(image.Rect(0, 0, 4, 4)), RepeatBoth)
+	bilinear.SetScaleStyle(BiLinear)
+	patterns := []Pattern{
+		NewSolidPattern(color.NRGBA{10, 20, 30, 40}),
+		linear,
+		stops(NewLinearGradient(0, 0, 0, 0)),
+		stops(NewRadialGradient(30, 50, 0, 70, 50, 50)),
+		oklab,
+		stops(NewConicGradient(50, 50, 30)),
+		stops(NewNoisePattern(NoiseSimplex, 20, 2, 1)),
+		NewSurfacePattern(gridImage(), RepeatBoth),
+		mirror,
+		bilinear,
+		NewTransformedPattern(linear, Rotate(0.3).Scale(1.5, 0.8)),
+		NewTransformedPattern(bilinear, Shear(0.4, 0).Translate(2.5, 1)),
+		NewTransformedPattern(NewCheckerboardPattern(6, color.White, color.Black), Rotate(1)),
+		NewTransformedPattern(linear, Scale(0, 1)),
+		NewPerspectivePattern(mirror, 20, 20, [4]Point{{0, 0}, {100, 0}, {60, 40}, {40, 40}}),
+	}
+	row := make([]color.RGBA64, 140)
+	for i, p := range patterns {
+		sp, ok := p.(SpanPattern)
+		if !ok {
+			t.Fatalf("pattern %d does not implement SpanPattern", i)
+		}
+		for y := -20; y < 120; y += 7 {
+			sp.FillSpan(row, -20, y)
+			for k, c := range row {
+				r, g, b, a := p.ColorAt(k-20, y).RGBA()
+				if c != (color.RGBA64{uint16(r), uint16(g), uint16(b), uint16(a)}) {
+					t.Fatalf("pattern %d at (%d, %d): got %v, want %v", i, k-20, y, c, []uint32{r, g, b, a})
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkLinearGradientFill(b *testing.B) {
+	dc := NewContext(1000, 1000)
+	g := NewLinearGradient(0, 0, 1000, 1000)
+	g.AddColorStop(0, color.Black)
+	g.AddColorStop(1, color.White)
+	dc.SetFillStyle(g)
+	for range b.N {
+		dc.DrawRectangle(0, 0, 1000, 1000)
+		dc.Fill()
+	}
+}
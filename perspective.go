@@ -0,0 +1,154 @@
+package gg
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/golang/freetype/raster"
+)
+
+// homography is a projective transformation in row-major order, mapping
+// (x, y) to ((h0 x + h1 y + h2) / w, (h3 x + h4 y + h5) / w) with
+// w = h6 x + h7 y + h8.
+type homography [9]float64
+
+// squareToQuad returns the homography that maps the corners (0, 0), (1, 0),
+// (1, 1) and (0, 1) of the unit square to q, see Heckbert, "Fundamentals of
+// Texture Mapping and Image Warping".
+func squareToQuad(q [4]Point) (homography, bool) {
+	dx1, dx2, dx3 := q[1].X-q[2].X, q[3].X-q[2].X, q[0].X-q[1].X+q[2].X-q[3].X
+	dy1, dy2, dy3 := q[1].Y-q[2].Y, q[3].Y-q[2].Y, q[0].Y-q[1].Y+q[2].Y-q[3].Y
+	var g, h float64
+	if dx3 != 0 || dy3 != 0 {
+		det := dx1*dy2 - dx2*dy1
+		if det == 0 {
+			return homography{}, false
+		}
+		g = (dx3*dy2 - dx2*dy3) / det
+		h = (dx1*dy3 - dx3*dy1) / det
+	}
+	return homography{
+		q[1].X - q[0].X + g*q[1].X, q[3].X - q[0].X + h*q[3].X, q[0].X,
+		q[1].Y - q[0].Y + g*q[1].Y, q[3].Y - q[0].Y + h*q[3].Y, q[0].Y,
+		g, h, 1,
+	}, true
+}
+
+// invert returns the inverse of m, if it has one.
+func (m homography) invert() (homography, bool) {
+	a := homography{
+		m[4]*m[8] - m[5]*m[7], m[2]*m[7] - m[1]*m[8], m[1]*m[5] - m[2]*m[4],
+		m[5]*m[6] - m[3]*m[8], m[0]*m[8] - m[2]*m[6], m[2]*m[3] - m[0]*m[5],
+		m[3]*m[7] - m[4]*m[6], m[1]*m[6] - m[0]*m[7], m[0]*m[4] - m[1]*m[3],
+	}
+	det := m[0]*a[0] + m[1]*a[3] + m[2]*a[6]
+	if det == 0 {
+		return homography{}, false
+	}
+	for i := range a {
+		a[i] /= det
+	}
+	return a, true
+}
+
+// Perspective Pattern
+type perspectivePattern struct {
+	p       Pattern
+	inv     homography
+	invalid bool
+}
+
+func (p *perspectivePattern) ColorAt(x, y int) color.Color {
+	if p.invalid {
+		return color.Transparent
+	}
+	px, py, ok := p.at(float64(x), float64(y))
+	if !ok {
+		return color.Transparent
+	}
+	return colorAtF(p.p, px, py)
+}
+
+// FillSpan satisfies the SpanPattern interface.
+func (p *perspectivePattern) FillSpan(dst []color.RGBA64, x, y int) {
+	p.fillSpan(dst, x, y, false)
+}
+
+func (p *perspectivePattern) fillSpanDeep(dst []color.RGBA64, x, y int) {
+	p.fillSpan(dst, x, y, true)
+}
+
+func (p *perspectivePattern) fillSpan(dst []color.RGBA64, x0, y int, deep bool) {
+	if p.invalid {
+		clear(dst)
+		return
+	}
+	fy := float64(y)
+	fillPoints(p.p, dst, func(i int) (float64, float64, bool) {
+		return p.at(float64(x0+i), fy)
+	}, deep)
+}
+
+// at returns the point of pattern space that the device pixel (x, y)
+// shows, or false if it lies beyond the horizon.
+func (p *perspectivePattern) at(x, y float64) (float64, float64, bool) {
+	// map the pixel center back into pattern space
+	m := &p.inv
+	fx, fy := x+0.5, y+0.5
+	w := m[6]*fx + m[7]*fy + m[8]
+	if w <= 0 {
+		return 0, 0, false
+	}
+	px := (m[0]*fx + m[1]*fy + m[2]) / w
+	py := (m[3]*fx + m[4]*fy + m[5]) / w
+	return px - 0.5, py - 0.5, true
+}
+
+// NewPerspectivePattern creates a pattern that draws the rectangle from
+// (0, 0) to (w, h) of p in perspective, with its top-left, top-right,
+// bottom-right and bottom-left corners at the device space points of
+// corners. Outside of the rectangle, p continues in perspective too.
+//
+// NewPerspectivePattern 创建一个以透视方式绘制 p 中从 (0, 0) 到 (w, h) 的矩形的图案，
+// 其左上、右上、右下与左下角分别位于设备空间中的 corners 各点。矩形之外的 p 同样按透视延伸。
+func NewPerspectivePattern(p Pattern, w, h float64, corners [4]Point) Pattern {
+	m, ok := squareToQuad(corners)
+	if ok && w != 0 && h != 0 {
+		// scale the rectangle to the unit square first
+		for _, i := range [3]int{0, 3, 6} {
+			m[i] /= w
+			m[i+1] /= h
+		}
+		m, ok = m.invert()
+	}
+	return &perspectivePattern{p: p, inv: m, invalid: !ok || w == 0 || h == 0}
+}
+
+// DrawImageQuad draws the specified image in perspective, with its
+// top-left, top-right, bottom-right and bottom-left corners at the points
+// of corners, like a picture on a screen or a sign. The corners are
+// transformed by the current matrix, the edges are antialiased and the
+// image is interpolated with the current scale style.
+//
+// DrawImageQuad 以透视方式绘制指定图像，其左上、右上、右下与左下角分别位于 corners 各点，
+// 如同屏幕或招牌上的画面。角点受当前矩阵变换，边缘抗锯齿，图像使用当前缩放样式插值。
+func (dc *Context) DrawImageQuad(im image.Image, corners [4]Point) {
+	b := im.Bounds()
+	if b.Empty() {
+		return
+	}
+	var path raster.Path
+	for i, c := range corners {
+		corners[i].X, corners[i].Y = dc.matrix.TransformPoint(c.X, c.Y)
+		if i == 0 {
+			path.Start(corners[i].Fixed())
+		} else {
+			path.Add1(corners[i].Fixed())
+		}
+	}
+	path.Add1(corners[0].Fixed())
+	sp := NewSurfacePattern(im, RepeatNone)
+	sp.SetScaleStyle(dc.scaleStyle)
+	p := NewPerspectivePattern(sp, float64(b.Dx()), float64(b.Dy()), corners)
+	dc.rasterize(path, true, newPatternPainter(dc.im, dc.mask, p))
+}
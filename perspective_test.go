@@ -0,0 +1,81 @@
+package gg
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestHomography(t *testing.T) {
+	quad := [4]Point{{10, 20}, {90, 5}, {120, 100}, {0, 70}}
+	m, ok := squareToQuad(quad)
+	if !ok {
+		t.Fatal("expected a homography")
+	}
+	inv, ok := m.invert()
+	if !ok {
+		t.Fatal("expected an inverse")
+	}
+	apply := func(m homography, x, y float64) (float64, float64) {
+		w := m[6]*x + m[7]*y + m[8]
+		return (m[0]*x + m[1]*y + m[2]) / w, (m[3]*x + m[4]*y + m[5]) / w
+	}
+	for i, p := range [4]Point{{0, 0}, {1, 0}, {1, 1}, {0, 1}} {
+		x, y := apply(m, p.X, p.Y)
+		if math.Abs(x-quad[i].X) > 1e-9 || math.Abs(y-quad[i].Y) > 1e-9 {
+			t.Errorf("corner %d: expected %v, got (%v, %v)", i, quad[i], x, y)
+		}
+		u, v := apply(inv, x, y)
+		if math.Abs(u-p.X) > 1e-9 || math.Abs(v-p.Y) > 1e-9 {
+			t.Errorf("corner %d: expected the inverse to give %v, got (%v, %v)", i, p, u, v)
+		}
+	}
+}
+
+func TestDrawImageQuad(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 20, 10))
+	for y := range 10 {
+		for x := range 20 {
+			c := color.RGBA{255, 0, 0, 255}
+			if x >= 10 {
+				c = color.RGBA{0, 0, 255, 255}
+			}
+			src.SetRGBA(x, y, c)
+		}
+	}
+	// an axis-aligned quad is a plain copy
+	dc := NewContext(40, 30)
+	dc.DrawImageQuad(src, [4]Point{{10, 10}, {30, 10}, {30, 20}, {10, 20}})
+	im := dc.im.(*image.RGBA)
+	for y := range 10 {
+		for x := range 20 {
+			if got, want := im.RGBAAt(x+10, y+10), src.RGBAAt(x, y); got != want {
+				t.Fatalf("pixel (%d, %d): expected %v, got %v", x, y, want, got)
+			}
+		}
+	}
+	if im.RGBAAt(5, 5).A != 0 || im.RGBAAt(35, 25).A != 0 {
+		t.Errorf("expected transparency outside the quad")
+	}
+
+	// a trapezoid keeps the halves on their sides
+	dc = NewContext(100, 100)
+	dc.SetScaleStyle(BiLinear)
+	dc.DrawImageQuad(src, [4]Point{{30, 10}, {70, 10}, {95, 90}, {5, 90}})
+	im = dc.im.(*image.RGBA)
+	for _, tc := range []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{35, 20, color.RGBA{255, 0, 0, 255}},
+		{65, 20, color.RGBA{0, 0, 255, 255}},
+		{25, 70, color.RGBA{255, 0, 0, 255}},
+		{75, 70, color.RGBA{0, 0, 255, 255}},
+		{10, 20, color.RGBA{}},
+	} {
+		if got := im.RGBAAt(tc.x, tc.y); !sameColor(got, tc.want) {
+			t.Errorf("pixel (%d, %d): expected %v, got %v", tc.x, tc.y, tc.want, got)
+		}
+	}
+}
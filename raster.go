@@ -0,0 +1,275 @@
+package gg
+
+import (
+	"image"
+	"image/color"
+	"sync"
+
+	"github.com/golang/freetype/raster"
+	"golang.org/x/image/math/fixed"
+)
+
+// Antialias defines the anti-aliasing quality used when rasterizing paths.
+//
+// Antialias 定义栅格化路径时使用的抗锯齿质量。
+type Antialias int
+
+// Anti-aliasing modes.
+//
+// 抗锯齿模式。
+const (
+	// AntialiasDefault uses the analytic pixel coverage computed by the
+	// rasterizer.
+	//
+	// AntialiasDefault 使用栅格化器计算的解析像素覆盖率。
+	AntialiasDefault Antialias = iota
+
+	// AntialiasNone paints a pixel either fully or not at all, depending on
+	// whether at least half of it is covered. No intermediate colors are
+	// produced, which suits pixel art and paletted images such as GIF frames.
+	//
+	// AntialiasNone 根据像素是否被覆盖一半以上，将其完全绘制或完全不绘制。
+	// 不会产生中间颜色，适用于像素画和 GIF 帧等调色板图像。
+	AntialiasNone
+
+	// AntialiasBest rasterizes paths with 4x4 supersampling, which gives
+	// smoother thin curves at the cost of speed and memory.
+	//
+	// AntialiasBest 以 4x4 超采样栅格化路径，以速度和内存为代价使细曲线更加平滑。
+	AntialiasBest
+)
+
+// supersampling is the number of samples per pixel and axis used by
+// AntialiasBest.
+const supersampling = 4
+
+// SetAntialias sets the anti-aliasing mode used for filling, stroking,
+// clipping and text rendering.
+//
+// SetAntialias 设置填充、描边、裁剪和文本渲染时使用的抗锯齿模式。
+func (dc *Context) SetAntialias(a Antialias) {
+	dc.antialias = a
+}
+
+// minBandHeight is the minimum height of the bands rasterized in parallel.
+// Above 120 pixels, the rasterizer splits curves the same way whatever its
+// size, so the bands produce exactly the same pixels as a single pass.
+const minBandHeight = 128
+
+// SetParallel enables rasterizing and painting fills, strokes and clips in
+// horizontal bands on as many goroutines as Workers returns when it is
+// called, which speeds up drawing large shapes on large canvases. The
+// output is identical to drawing serially. Patterns used while it is
+// enabled must be safe for concurrent use; all patterns of this package
+// are. Paths rasterized with AntialiasBest are still drawn serially.
+//
+// SetParallel 启用按水平条带并行栅格化和绘制填充、描边与裁剪，协程数为调用时 Workers 的返回值，
+// 可加快在大画布上绘制大图形的速度。输出与串行绘制完全相同。
+// 启用时使用的图案须能安全地并发使用，本包的所有图案均满足此要求。
+// 使用 AntialiasBest 栅格化的路径仍以串行方式绘制。
+func (dc *Context) SetParallel(parallel bool) {
+	dc.workers = 1
+	if parallel {
+		dc.workers = Workers()
+	}
+}
+
+// rasterize paints path, whose coordinates are in device space, with
+// painter honoring the anti-aliasing mode of the context.
+func (dc *Context) rasterize(path raster.Path, nonZero bool, painter raster.Painter) {
+	if dc.antialias == AntialiasBest {
+		dc.rasterizeSupersampled(path, nonZero, painter)
+		return
+	}
+	if dc.workers > 1 && dc.rasterizeBands(path, nonZero, painter) {
+		return
+	}
+	dc.rasterizeWith(dc.rasterizer, path, nonZero, painter)
+}
+
+// rasterizeWith rasterizes path with r.
+func (dc *Context) rasterizeWith(r *raster.Rasterizer, path raster.Path, nonZero bool, painter raster.Painter) {
+	if dc.antialias == AntialiasNone {
+		painter = raster.NewMonochromePainter(painter)
+	}
+	r.UseNonZeroWinding = nonZero
+	r.Clear()
+	r.AddPath(path)
+	r.Rasterize(painter)
+}
+
+// rasterizeBands rasterizes path concurrently in the horizontal bands it
+// touches. It returns false without drawing anything if path lies within
+// a single band.
+func (dc *Context) rasterizeBands(path raster.Path, nonZero bool, painter raster.Painter) bool {
+	n := min(dc.workers, dc.height/minBandHeight)
+	if n < 2 {
+		return false
+	}
+	// every band is at least minBandHeight high
+	band := func(i int) (y0, y1 int) {
+		return i * dc.height / n, (i + 1) * dc.height / n
+	}
+	b := pathBounds(path)
+	first, last := n, -1
+	for i := range n {
+		if y0, y1 := band(i); b.Min.Y < y1 && b.Max.Y > y0 {
+			first, last = min(first, i), i
+		}
+	}
+	if last <= first {
+		return false
+	}
+	if len(dc.bands) != n {
+		dc.bands = make([]*raster.Rasterizer, n)
+	}
+	var wg sync.WaitGroup
+	for i := first; i <= last; i++ {
+		y0, y1 := band(i)
+		// The rasterizer rounds negative coordinates differently, so the path
+		// is only moved up as far as it stays below the top of the image.
+		// The rows between the top of the rasterizer and the band are then
+		// accumulated again but not painted.
+		top := max(0, min(y0, b.Min.Y))
+		if dc.bands[i] == nil {
+			dc.bands[i] = raster.NewRasterizer(dc.width, y1-top)
+		} else {
+			dc.bands[i].SetBounds(dc.width, y1-top)
+		}
+		r := dc.bands[i]
+		r.Dy = top
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// moving by whole pixels keeps the coverage of every cell
+			dy := fixed.Int26_6(top << 6)
+			shifted := mapPath(path, func(p fixed.Point26_6) fixed.Point26_6 {
+				return fixed.Point26_6{X: p.X, Y: p.Y - dy}
+			})
+			dc.rasterizeWith(r, shifted, nonZero, bandPainter(painter, y0))
+		}()
+	}
+	wg.Wait()
+	return true
+}
+
+// bandPainter returns a painter that paints like p the rows from y0 on
+// and can run concurrently with p on other rows.
+func bandPainter(p raster.Painter, y0 int) raster.Painter {
+	if pp, ok := p.(*patternPainter); ok {
+		c := *pp
+		c.row = nil
+		p = &c
+	}
+	return raster.PainterFunc(func(ss []raster.Span, done bool) {
+		i := 0
+		for i < len(ss) && ss[i].Y < y0 {
+			i++
+		}
+		p.Paint(ss[i:], done)
+	})
+}
+
+// rasterizeSupersampled rasterizes path at a higher resolution restricted to
+// its bounding box and paints the averaged coverage with painter.
+func (dc *Context) rasterizeSupersampled(path raster.Path, nonZero bool, painter raster.Painter) {
+	const ss = supersampling
+	b := pathBounds(path).Intersect(image.Rect(0, 0, dc.width, dc.height))
+	if b.Empty() {
+		painter.Paint(nil, true)
+		return
+	}
+	bw, bh := b.Dx(), b.Dy()
+	org := fixed.Point26_6{X: fixed.Int26_6(b.Min.X << 6), Y: fixed.Int26_6(b.Min.Y << 6)}
+	scaled := mapPath(path, func(p fixed.Point26_6) fixed.Point26_6 {
+		return p.Sub(org).Mul(fixed.Int26_6(ss << 6))
+	})
+	coverage := make([]uint32, bw*bh)
+	r := raster.NewRasterizer(bw*ss, bh*ss)
+	r.UseNonZeroWinding = nonZero
+	r.AddPath(scaled)
+	r.Rasterize(raster.PainterFunc(func(spans []raster.Span, _ bool) {
+		for _, s := range spans {
+			row := coverage[s.Y/ss*bw:]
+			for x := s.X0; x < s.X1; x++ {
+				row[x/ss] += s.Alpha
+			}
+		}
+	}))
+	var buf [64]raster.Span
+	spans := buf[:0]
+	for y := range bh {
+		row := coverage[y*bw : (y+1)*bw]
+		for x := 0; x < bw; {
+			a := row[x] / (ss * ss)
+			x0 := x
+			for x++; x < bw && row[x]/(ss*ss) == a; x++ {
+			}
+			if a == 0 {
+				continue
+			}
+			spans = append(spans, raster.Span{Y: b.Min.Y + y, X0: b.Min.X + x0, X1: b.Min.X + x, Alpha: a})
+			if len(spans) == cap(spans) {
+				painter.Paint(spans, false)
+				spans = spans[:0]
+			}
+		}
+	}
+	painter.Paint(spans, true)
+}
+
+// mapPath returns a copy of p with every point, including control points,
+// mapped by f.
+func mapPath(p raster.Path, f func(fixed.Point26_6) fixed.Point26_6) raster.Path {
+	q := make(raster.Path, len(p))
+	copy(q, p)
+	for i := 0; i < len(q); {
+		n := int(q[i])
+		if n < 0 || n > 3 {
+			break
+		}
+		for j := i + 1; j < i+1+2*max(n, 1); j += 2 {
+			m := f(fixed.Point26_6{X: q[j], Y: q[j+1]})
+			q[j], q[j+1] = m.X, m.Y
+		}
+		i += 2 + 2*max(n, 1)
+	}
+	return q
+}
+
+// pathBounds returns the pixel bounds of every point of p, including control
+// points, which always contains the rasterized path.
+func pathBounds(p raster.Path) image.Rectangle {
+	var b fixed.Rectangle26_6
+	first := true
+	mapPath(p, func(pt fixed.Point26_6) fixed.Point26_6 {
+		if first {
+			b.Min, b.Max = pt, pt
+			first = false
+		}
+		b.Min.X, b.Min.Y = min(b.Min.X, pt.X), min(b.Min.Y, pt.Y)
+		b.Max.X, b.Max.Y = max(b.Max.X, pt.X), max(b.Max.Y, pt.Y)
+		return pt
+	})
+	if first {
+		return image.Rectangle{}
+	}
+	return image.Rect(b.Min.X.Floor(), b.Min.Y.Floor(), b.Max.X.Ceil()+1, b.Max.Y.Ceil()+1)
+}
+
+// monochromeMask quantizes the alpha of a mask image to fully opaque or
+// fully transparent.
+type monochromeMask struct {
+	image.Image
+}
+
+func (m monochromeMask) ColorModel() color.Model {
+	return color.AlphaModel
+}
+
+func (m monochromeMask) At(x, y int) color.Color {
+	if _, _, _, a := m.Image.At(x, y).RGBA(); a >= 0x8000 {
+		return color.Opaque
+	}
+	return color.Transparent
+}
@@ -0,0 +1,116 @@
+package gg
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestAntialiasNone(t *testing.T) {
+	dc := NewContext(100, 100)
+	dc.SetRGB(1, 1, 1)
+	dc.Clear()
+	dc.SetAntialias(AntialiasNone)
+	dc.DrawCircle(50, 50, 30.3)
+	dc.SetRGB(1, 0, 0)
+	dc.Fill()
+	dc.DrawLine(5, 95, 95, 20)
+	dc.SetLineWidth(2.5)
+	dc.SetRGB(0, 0, 1)
+	if err := dc.Stroke(); err != nil {
+		t.Fatal(err)
+	}
+	dc.SetRGB(0, 0, 0)
+	dc.DrawString("Hi", 40, 55)
+	if err := saveImage(dc, "TestAntialiasNone"); err != nil {
+		t.Fatal(err)
+	}
+	allowed := map[color.RGBA]bool{White: true, Red: true, Blue: true, Black: true}
+	for y := range 100 {
+		for x := range 100 {
+			if c := dc.im.(*image.RGBA).RGBAAt(x, y); !allowed[c] {
+				t.Fatalf("unexpected blended color %v at (%d, %d)", c, x, y)
+			}
+		}
+	}
+}
+
+func TestAntialiasBest(t *testing.T) {
+	render := func(a Antialias) *Context {
+		dc := NewContext(100, 100)
+		dc.SetAntialias(a)
+		dc.SetRGB(0, 0, 0)
+		dc.DrawCircle(50.3, 49.6, 37.25)
+		dc.Fill()
+		return dc
+	}
+	best := render(AntialiasBest)
+	if err := saveImage(best, "TestAntialiasBest"); err != nil {
+		t.Fatal(err)
+	}
+	if best.String() == render(AntialiasDefault).String() {
+		t.Fatal("supersampled rendering should differ from the default")
+	}
+	area := math.Pi * 37.25 * 37.25
+	if got := coverage(best); math.Abs(got-area)/area > 0.005 {
+		t.Fatalf("expected area %.1f, got %.1f", area, got)
+	}
+}
+
+func TestParallel(t *testing.T) {
+	render := func(parallel bool, a Antialias) *Context {
+		dc := NewContext(90, 600)
+		dc.SetParallel(parallel)
+		if parallel {
+			// as many bands as fit, whatever the number of CPUs
+			dc.workers = 4
+		}
+		dc.SetAntialias(a)
+		dc.SetRGB(1, 1, 1)
+		dc.Clear()
+		g := NewLinearGradient(0, 0, 90, 600)
+		g.AddColorStop(0, color.RGBA{255, 0, 0, 255})
+		g.AddColorStop(1, color.RGBA{0, 0, 255, 128})
+		dc.SetFillStyle(g)
+		dc.DrawEllipse(45, 300, 40, 290)
+		dc.Fill()
+		dc.SetRGBA(1, 0.5, 0, 0.5)
+		dc.DrawCircle(45, -20, 200.3)
+		dc.Fill()
+		dc.DrawRegularPolygon(7, 45, 300, 250, 0.3)
+		dc.Clip()
+		dc.SetRGBA(0, 0.5, 0, 0.7)
+		dc.SetLineWidth(7)
+		for y := 10.3; y < 600; y += 37.7 {
+			dc.MoveTo(0, y)
+			dc.CubicTo(30, y-40, 60, y+40, 90, y+3)
+		}
+		if err := dc.Stroke(); err != nil {
+			t.Fatal(err)
+		}
+		dc.ResetClip()
+		dc.SetDash(5, 3)
+		dc.DrawRectangle(3.5, 3.5, 83, 593)
+		if err := dc.Stroke(); err != nil {
+			t.Fatal(err)
+		}
+		return dc
+	}
+	for _, a := range []Antialias{AntialiasDefault, AntialiasNone, AntialiasBest} {
+		checkHash(t, render(true, a), render(false, a).String())
+	}
+}
+
+func BenchmarkParallelFill(b *testing.B) {
+	dc := NewContext(4000, 4000)
+	dc.SetParallel(true)
+	g := NewRadialGradient(2000, 2000, 0, 2000, 2000, 2000)
+	g.AddColorStop(0, color.Black)
+	g.AddColorStop(1, color.White)
+	dc.SetFillStyle(g)
+	for range b.N {
+		dc.DrawCircle(2000, 2000, 1900)
+		dc.Fill()
+	}
+}
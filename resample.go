@@ -0,0 +1,223 @@
+package gg
+
+import (
+	"image"
+	"math"
+
+	"golang.org/x/image/draw"
+
+	"github.com/FloatTech/gg/internal/pixop"
+)
+
+// Resampling kernels beyond those of x/image/draw.
+var (
+	mitchellKernel = &draw.Kernel{Support: 2, At: bcSpline(1.0/3, 1.0/3)}
+	lanczos2Kernel = &draw.Kernel{Support: 2, At: lanczos(2)}
+	lanczos3Kernel = &draw.Kernel{Support: 3, At: lanczos(3)}
+	// a hair over half a pixel, so samples halfway between two pixels
+	// still see both of them
+	areaKernel = &draw.Kernel{Support: 0.5 + 1e-6, At: func(float64) float64 { return 1 }}
+)
+
+// bcSpline returns the cubic BC-spline kernel with the parameters b and c.
+func bcSpline(b, c float64) func(t float64) float64 {
+	return func(t float64) float64 {
+		t = math.Abs(t)
+		if t < 1 {
+			return ((12-9*b-6*c)*t*t*t + (-18+12*b+6*c)*t*t + (6 - 2*b)) / 6
+		}
+		if t < 2 {
+			return ((-b-6*c)*t*t*t + (6*b+30*c)*t*t + (-12*b-48*c)*t + (8*b + 24*c)) / 6
+		}
+		return 0
+	}
+}
+
+// lanczos returns the Lanczos kernel with a lobes.
+func lanczos(a float64) func(t float64) float64 {
+	return func(t float64) float64 {
+		t = math.Abs(t)
+		if t == 0 {
+			return 1
+		}
+		if t >= a {
+			return 0
+		}
+		x := math.Pi * t
+		return a * math.Sin(x) * math.Sin(x/a) / (x * x)
+	}
+}
+
+// kernel returns the resampling kernel of s, or nil if its interpolator
+// is not a kernel.
+func (s ScaleStyle) kernel() *draw.Kernel {
+	k, _ := s.transformer().(*draw.Kernel)
+	return k
+}
+
+// Resize returns the image im scaled to w x h pixels with the
+// interpolation style. If one of w and h is 0 or less, it is computed from
+// the other to keep the aspect ratio; if both are, or the size does not
+// change, the pixels are copied as they are.
+// The interpolation works on premultiplied colors, so transparent pixels
+// do not bleed into their neighbours, and the rows are scaled in parallel.
+// The result starts at the origin.
+//
+// Resize 返回使用插值样式 style 缩放到 w x h 像素的图像 im。
+// 若 w 与 h 之一不大于 0，则按另一个保持宽高比计算；若都不大于 0 或尺寸不变，则原样复制像素。
+// 插值在预乘颜色上进行，因此透明像素不会渗入相邻像素，且各行并行缩放。结果从原点开始。
+func Resize(im image.Image, w, h int, style ScaleStyle) *image.NRGBA {
+	return resize(im, w, h, style, Workers())
+}
+
+// resize is Resize on the given number of goroutines.
+func resize(im image.Image, w, h int, style ScaleStyle, workers int) *image.NRGBA {
+	sr := im.Bounds()
+	switch {
+	case w <= 0 && h <= 0:
+		w, h = sr.Dx(), sr.Dy()
+	case w <= 0:
+		w = max(1, int(math.Round(float64(h)*float64(sr.Dx())/float64(sr.Dy()))))
+	case h <= 0:
+		h = max(1, int(math.Round(float64(w)*float64(sr.Dy())/float64(sr.Dx()))))
+	}
+	dr := image.Rect(0, 0, w, h)
+	if sr.Empty() {
+		return image.NewNRGBA(dr)
+	}
+	if dr.Size() == sr.Size() {
+		dst := image.NewNRGBA(dr)
+		draw.Draw(dst, dr, im, sr.Min, draw.Src)
+		return dst
+	}
+	if k := style.kernel(); k != nil {
+		return resizeKernel(im, w, h, k, workers)
+	}
+	// the remaining scalers only compute the destination pixels inside the
+	// band and map dr onto sr the same way for every band
+	tmp := image.NewRGBA(dr)
+	scaler := style.transformer()
+	pixop.Rows(workers, h, func(y0, y1 int) {
+		band := tmp.SubImage(image.Rect(0, y0, w, y1)).(*image.RGBA)
+		scaler.Scale(band, dr, im, sr, draw.Src, nil)
+	})
+	dst := image.NewNRGBA(dr)
+	pixop.Rows(workers, h, func(y0, y1 int) {
+		for i := y0 * tmp.Stride; i < y1*tmp.Stride; i += 4 {
+			s, d := tmp.Pix[i:i+4:i+4], dst.Pix[i:i+4:i+4]
+			switch a := uint32(s[3]); a {
+			case 0:
+			case 255:
+				copy(d, s)
+			default:
+				d[0] = uint8(min(255, (uint32(s[0])*255+a/2)/a))
+				d[1] = uint8(min(255, (uint32(s[1])*255+a/2)/a))
+				d[2] = uint8(min(255, (uint32(s[2])*255+a/2)/a))
+				d[3] = s[3]
+			}
+		}
+	})
+	return dst
+}
+
+// resampleTaps holds the weights of the source pixels from i onwards that
+// make up one destination pixel.
+type resampleTaps struct {
+	i int
+	w []float32
+}
+
+// newResampleTaps returns the taps of the kernel k for scaling sn pixels
+// to dn, widening k when shrinking so every source pixel counts, like the
+// kernel scalers of x/image/draw.
+func newResampleTaps(k *draw.Kernel, dn, sn int) []resampleTaps {
+	scale := float64(sn) / float64(dn)
+	support, argScale := k.Support, 1.0
+	if scale > 1 {
+		support *= scale
+		argScale = 1 / scale
+	}
+	taps := make([]resampleTaps, dn)
+	for d := range taps {
+		center := (float64(d)+0.5)*scale - 0.5
+		i := max(0, int(math.Floor(center-support)))
+		j := max(i, min(sn, int(math.Ceil(center+support))))
+		w := make([]float32, j-i)
+		var total float64
+		for s := i; s < j; s++ {
+			if t := math.Abs(float64(s)-center) * argScale; t < k.Support {
+				v := k.At(t)
+				w[s-i] = float32(v)
+				total += v
+			}
+		}
+		if total != 0 {
+			for n := range w {
+				w[n] /= float32(total)
+			}
+		}
+		taps[d] = resampleTaps{i: i, w: w}
+	}
+	return taps
+}
+
+// resizeKernel is Resize with the kernel k. Every band of destination rows
+// filters the source rows under its kernels horizontally once, keeping only
+// as many of them as one destination row needs, and then blends them
+// vertically.
+func resizeKernel(im image.Image, w, h int, k *draw.Kernel, workers int) *image.NRGBA {
+	sr := im.Bounds()
+	xt, yt := newResampleTaps(k, w, sr.Dx()), newResampleTaps(k, h, sr.Dy())
+	n := 1
+	for _, t := range yt {
+		n = max(n, len(t.w))
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	pixop.Rows(workers, h, func(y0, y1 int) {
+		// the horizontally filtered source row sy is in the slot sy % n
+		rows := make([]float32, n*4*w)
+		acc := make([]float32, 4*w)
+		line := image.NewRGBA(image.Rect(sr.Min.X, 0, sr.Max.X, 1))
+		next := 0
+		for y := y0; y < y1; y++ {
+			t := yt[y]
+			for sy := max(next, t.i); sy < t.i+len(t.w); sy++ {
+				draw.Draw(line, line.Rect, im, image.Pt(sr.Min.X, sr.Min.Y+sy), draw.Src)
+				row := rows[sy%n*4*w : (sy%n+1)*4*w]
+				for x, tx := range xt {
+					var r, g, b, a float32
+					for i, v := range tx.w {
+						p := line.Pix[4*(tx.i+i) : 4*(tx.i+i)+4 : 4*(tx.i+i)+4]
+						r += v * float32(p[0])
+						g += v * float32(p[1])
+						b += v * float32(p[2])
+						a += v * float32(p[3])
+					}
+					row[4*x], row[4*x+1], row[4*x+2], row[4*x+3] = r, g, b, a
+				}
+				next = sy + 1
+			}
+			clear(acc)
+			for i, v := range t.w {
+				sy := t.i + i
+				row := rows[sy%n*4*w : (sy%n+1)*4*w]
+				for x, c := range row {
+					acc[x] += v * c
+				}
+			}
+			// back to straight alpha, clamping the ringing of the kernel
+			d := dst.Pix[y*dst.Stride : y*dst.Stride+4*w]
+			for x := 0; x < len(d); x += 4 {
+				a := min(255, acc[x+3])
+				if a < 0.5 {
+					continue
+				}
+				for c := range 3 {
+					d[x+c] = uint8(max(0, min(a, acc[x+c]))*255/a + 0.5)
+				}
+				d[x+3] = uint8(a + 0.5)
+			}
+		}
+	})
+	return dst
+}
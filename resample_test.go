@@ -0,0 +1,114 @@
+package gg
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+var allScaleStyles = []ScaleStyle{BiLinear, ApproxBiLinear, NearestNeighbor, CatmullRom, Mitchell, Lanczos2, Lanczos3, Area}
+
+func TestResize(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(5, 5, 45, 25))
+	for i := 0; i < len(src.Pix); i += 4 {
+		copy(src.Pix[i:], []uint8{200, 100, 50, 255})
+	}
+	want := color.NRGBA{200, 100, 50, 255}
+	for _, style := range allScaleStyles {
+		for _, size := range []image.Point{{13, 7}, {97, 61}} {
+			out := Resize(src, size.X, size.Y, style)
+			if out.Rect != image.Rect(0, 0, size.X, size.Y) {
+				t.Fatalf("style %d: expected size %v, got %v", style, size, out.Rect)
+			}
+			for _, p := range []image.Point{{0, 0}, {size.X / 2, size.Y / 2}, {size.X - 1, size.Y - 1}} {
+				if got := out.NRGBAAt(p.X, p.Y); !sameColor(got, want) {
+					t.Errorf("style %d, size %v: pixel %v: expected %v, got %v", style, size, p, want, got)
+				}
+			}
+		}
+	}
+	if r := Resize(src, 20, 0, Lanczos3).Rect; r != image.Rect(0, 0, 20, 10) {
+		t.Errorf("expected the aspect ratio to be kept, got %v", r)
+	}
+	if r := Resize(src, 0, 0, Lanczos3).Rect; r != image.Rect(0, 0, 40, 20) {
+		t.Errorf("expected the natural size, got %v", r)
+	}
+}
+
+func TestResizePremultiplied(t *testing.T) {
+	// transparent green around an opaque red pixel must not tint it
+	src := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for i := 0; i < len(src.Pix); i += 4 {
+		copy(src.Pix[i:], []uint8{0, 255, 0, 0})
+	}
+	src.SetNRGBA(4, 4, color.NRGBA{255, 0, 0, 255})
+	for _, style := range []ScaleStyle{BiLinear, Mitchell, Lanczos3, Area} {
+		out := Resize(src, 32, 32, style)
+		for i := 0; i < len(out.Pix); i += 4 {
+			if out.Pix[i+3] > 8 && out.Pix[i+1] > 8 {
+				t.Fatalf("style %d: green bled into %v", style, out.Pix[i:i+4])
+			}
+		}
+	}
+}
+
+func TestResizeArea(t *testing.T) {
+	// a checkerboard averages to gray
+	src := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := range 64 {
+		for x := range 64 {
+			if (x+y)%2 == 0 {
+				src.SetNRGBA(x, y, color.NRGBA{255, 255, 255, 255})
+			} else {
+				src.SetNRGBA(x, y, color.NRGBA{0, 0, 0, 255})
+			}
+		}
+	}
+	out := Resize(src, 8, 8, Area)
+	if got, want := out.NRGBAAt(3, 3), (color.NRGBA{128, 128, 128, 255}); !sameColor(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestScaleStyleKernels(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for i := 0; i < len(src.Pix); i += 4 {
+		copy(src.Pix[i:], []uint8{30, 60, 90, 255})
+	}
+	want := color.RGBA{30, 60, 90, 255}
+	for _, style := range allScaleStyles {
+		dc := NewContext(40, 40)
+		dc.SetScaleStyle(style)
+		dc.DrawImageRect(src, src.Rect, 0, 0, 40, 40)
+		if got := dc.im.(*image.RGBA).RGBAAt(20, 20); !sameColor(got, want) {
+			t.Errorf("style %d: DrawImageRect: expected %v, got %v", style, want, got)
+		}
+		p := NewSurfacePattern(src, RepeatBoth)
+		p.SetScaleStyle(style)
+		p.SetOrigin(0.3, 0.7)
+		if got := p.ColorAt(5, 5); !sameColor(got, want) {
+			t.Errorf("style %d: pattern: expected %v, got %v", style, want, got)
+		}
+	}
+}
+
+func TestResizeParallel(t *testing.T) {
+	src := ImageToNRGBA(gridImage())
+	for _, style := range allScaleStyles {
+		for _, size := range []image.Point{{37, 211}, {300, 17}} {
+			want := resize(src, size.X, size.Y, style, 1)
+			if got := resize(src, size.X, size.Y, style, 5); string(got.Pix) != string(want.Pix) {
+				t.Fatalf("style %d, size %v: expected the same pixels on more CPUs", style, size)
+			}
+		}
+	}
+}
+
+func BenchmarkResize(b *testing.B) {
+	src := image.NewNRGBA(image.Rect(0, 0, 4000, 3000))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		Resize(src, 2000, 1500, Lanczos3)
+	}
+}
@@ -0,0 +1,209 @@
+package gg
+
+import "math"
+
+// DrawRoundedRectangleCorners draws a rectangle at the given position whose
+// corners are rounded with independent radii, in the order top-left,
+// top-right, bottom-right and bottom-left. Radii too large for the
+// rectangle are scaled down proportionally, as CSS border-radius does.
+//
+// DrawRoundedRectangleCorners 在指定位置绘制四角半径各自独立的圆角矩形，
+// 半径依次为左上、右上、右下和左下。过大的半径会像 CSS border-radius 一样按比例缩小。
+func (dc *Context) DrawRoundedRectangleCorners(x, y, w, h, tl, tr, br, bl float64) {
+	tl, tr, br, bl = max(tl, 0), max(tr, 0), max(br, 0), max(bl, 0)
+	f := 1.0
+	for _, s := range [...][2]float64{{w, tl + tr}, {w, bl + br}, {h, tl + bl}, {h, tr + br}} {
+		if s[1] > s[0] {
+			f = min(f, s[0]/s[1])
+		}
+	}
+	tl, tr, br, bl = tl*f, tr*f, br*f, bl*f
+	dc.NewSubPath()
+	dc.MoveTo(x+tl, y)
+	dc.LineTo(x+w-tr, y)
+	if tr > 0 {
+		dc.DrawArc(x+w-tr, y+tr, tr, Radians(270), Radians(360))
+	}
+	dc.LineTo(x+w, y+h-br)
+	if br > 0 {
+		dc.DrawArc(x+w-br, y+h-br, br, Radians(0), Radians(90))
+	}
+	dc.LineTo(x+bl, y+h)
+	if bl > 0 {
+		dc.DrawArc(x+bl, y+h-bl, bl, Radians(90), Radians(180))
+	}
+	dc.LineTo(x, y+tl)
+	if tl > 0 {
+		dc.DrawArc(x+tl, y+tl, tl, Radians(180), Radians(270))
+	}
+	dc.ClosePath()
+}
+
+// regularPolygonPoints returns the vertices of a regular polygon laid out
+// the same way as DrawRegularPolygon.
+func regularPolygonPoints(n int, x, y, r, rotation float64) []Point {
+	if n <= 0 {
+		return nil
+	}
+	angle := 2 * math.Pi / float64(n)
+	rotation -= math.Pi / 2
+	if n%2 == 0 {
+		rotation += angle / 2
+	}
+	points := make([]Point, n)
+	for i := range points {
+		a := rotation + angle*float64(i)
+		points[i] = Point{x + r*math.Cos(a), y + r*math.Sin(a)}
+	}
+	return points
+}
+
+// starPoints returns the 2n vertices of a star with the first outer point
+// facing up before rotation.
+func starPoints(n int, x, y, outer, inner, rotation float64) []Point {
+	angle := math.Pi / float64(n)
+	rotation -= math.Pi / 2
+	points := make([]Point, 2*n)
+	for i := range points {
+		r := outer
+		if i%2 == 1 {
+			r = inner
+		}
+		a := rotation + angle*float64(i)
+		points[i] = Point{x + r*math.Cos(a), y + r*math.Sin(a)}
+	}
+	return points
+}
+
+// DrawStar draws a star polygon with n points centered at (x, y). The tips
+// lie on the outer radius and the notches on the inner radius.
+//
+// DrawStar 绘制以 (x, y) 为中心的 n 角星形。星尖位于外半径上，凹角位于内半径上。
+func (dc *Context) DrawStar(n int, x, y, outer, inner, rotation float64) {
+	if n < 2 {
+		return
+	}
+	dc.DrawPolygon(starPoints(n, x, y, outer, inner, rotation))
+}
+
+// DrawPolygon draws a closed polygon through the given points.
+//
+// DrawPolygon 绘制经过给定各点的闭合多边形。
+func (dc *Context) DrawPolygon(points []Point) {
+	dc.NewSubPath()
+	for _, p := range points {
+		dc.LineTo(p.X, p.Y)
+	}
+	dc.ClosePath()
+}
+
+// DrawRoundedPolygon draws a closed polygon through the given points whose
+// corners are rounded with radius r. The radius of each corner is reduced
+// if the adjacent edges are too short for it.
+//
+// DrawRoundedPolygon 绘制经过给定各点、以半径 r 圆角化的闭合多边形。
+// 若相邻边过短，对应角的半径会相应减小。
+func (dc *Context) DrawRoundedPolygon(points []Point, r float64) {
+	n := len(points)
+	if n < 3 || r <= 0 {
+		dc.DrawPolygon(points)
+		return
+	}
+	dc.NewSubPath()
+	last := points[n-1]
+	start := last.Interpolate(points[0], 0.5)
+	dc.MoveTo(start.X, start.Y)
+	for i, p := range points {
+		prev := points[(i+n-1)%n]
+		next := points[(i+1)%n]
+		lu, lv := p.Distance(prev), p.Distance(next)
+		if lu == 0 || lv == 0 {
+			continue
+		}
+		cos := ((prev.X-p.X)*(next.X-p.X) + (prev.Y-p.Y)*(next.Y-p.Y)) / (lu * lv)
+		half := math.Acos(max(-1, min(1, cos))) / 2
+		// the tangent points must stay within the first half of each edge
+		cr := min(r, min(lu, lv)/2*math.Tan(half))
+		dc.ArcTo(p.X, p.Y, next.X, next.Y, cr)
+	}
+	dc.ClosePath()
+}
+
+// DrawRoundedRegularPolygon draws a regular polygon with n sides centered
+// at (x, y) whose corners are rounded with radius cornerRadius.
+//
+// DrawRoundedRegularPolygon 绘制以 (x, y) 为中心、角半径为 cornerRadius 的 n 边正多边形。
+func (dc *Context) DrawRoundedRegularPolygon(n int, x, y, r, rotation, cornerRadius float64) {
+	if n < 3 {
+		return
+	}
+	dc.DrawRoundedPolygon(regularPolygonPoints(n, x, y, r, rotation), cornerRadius)
+}
+
+// DrawRoundedStar draws a star polygon like DrawStar with every corner
+// rounded with radius cornerRadius.
+//
+// DrawRoundedStar 绘制与 DrawStar 相同、且每个角以 cornerRadius 圆角化的星形。
+func (dc *Context) DrawRoundedStar(n int, x, y, outer, inner, rotation, cornerRadius float64) {
+	if n < 2 {
+		return
+	}
+	dc.DrawRoundedPolygon(starPoints(n, x, y, outer, inner, rotation), cornerRadius)
+}
+
+// DrawSector draws a circular sector (a pie slice) centered at (x, y)
+// between angle1 and angle2.
+//
+// DrawSector 绘制以 (x, y) 为中心、位于 angle1 与 angle2 之间的扇形（饼图切片）。
+func (dc *Context) DrawSector(x, y, r, angle1, angle2 float64) {
+	dc.NewSubPath()
+	dc.MoveTo(x, y)
+	dc.DrawRotatedEllipticalArc(x, y, r, r, 0, angle1, angle2)
+	dc.ClosePath()
+}
+
+// DrawAnnularSector draws the part of a ring centered at (x, y) between the
+// radii r0 and r1 and the angles angle1 and angle2, as used by donut charts.
+//
+// DrawAnnularSector 绘制以 (x, y) 为中心、位于半径 r0 与 r1、
+// 角度 angle1 与 angle2 之间的环形扇区，可用于环形图。
+func (dc *Context) DrawAnnularSector(x, y, r0, r1, angle1, angle2 float64) {
+	dc.NewSubPath()
+	dc.DrawRotatedEllipticalArc(x, y, r1, r1, 0, angle1, angle2)
+	if r0 > 0 {
+		dc.DrawRotatedEllipticalArc(x, y, r0, r0, 0, angle2, angle1)
+	} else {
+		dc.LineTo(x, y)
+	}
+	dc.ClosePath()
+}
+
+// DrawSuperellipse draws a superellipse (Lamé curve) centered at (x, y)
+// satisfying |x/rx|^n + |y/ry|^n = 1. n = 2 gives an ellipse, larger values
+// approach a rectangle.
+//
+// DrawSuperellipse 绘制以 (x, y) 为中心、满足 |x/rx|^n + |y/ry|^n = 1 的超椭圆（Lamé 曲线）。
+// n = 2 时为椭圆，n 越大越接近矩形。
+func (dc *Context) DrawSuperellipse(x, y, rx, ry, n float64) {
+	if n <= 0 {
+		return
+	}
+	e := 2 / n
+	steps := max(int(2*math.Pi*math.Max(math.Abs(rx), math.Abs(ry))/2), 64)
+	dc.NewSubPath()
+	for i := range steps {
+		s, c := math.Sincos(2 * math.Pi * float64(i) / float64(steps))
+		px := x + rx*math.Copysign(math.Pow(math.Abs(c), e), c)
+		py := y + ry*math.Copysign(math.Pow(math.Abs(s), e), s)
+		dc.LineTo(px, py)
+	}
+	dc.ClosePath()
+}
+
+// DrawSquircle draws a squircle, the superellipse with n = 4, centered at
+// (x, y) with radius r.
+//
+// DrawSquircle 绘制以 (x, y) 为中心、半径为 r 的方圆（n = 4 的超椭圆）。
+func (dc *Context) DrawSquircle(x, y, r float64) {
+	dc.DrawSuperellipse(x, y, r, r, 4)
+}
@@ -0,0 +1,58 @@
+package gg
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+// coverage sums the alpha of every pixel of the context, in pixels.
+func coverage(dc *Context) float64 {
+	var sum float64
+	pix := dc.im.(*image.RGBA).Pix
+	for i := 3; i < len(pix); i += 4 {
+		sum += float64(pix[i]) / 255
+	}
+	return sum
+}
+
+func TestShapeAreas(t *testing.T) {
+	cases := []struct {
+		name string
+		draw func(dc *Context)
+		area float64
+	}{
+		{"sector", func(dc *Context) { dc.DrawSector(50, 50, 40, 0, math.Pi/2) }, math.Pi * 40 * 40 / 4},
+		{"annular", func(dc *Context) { dc.DrawAnnularSector(50, 50, 20, 40, 0, math.Pi) }, math.Pi * (40*40 - 20*20) / 2},
+		{"corners", func(dc *Context) { dc.DrawRoundedRectangleCorners(10, 10, 80, 80, 0, 0, 0, 0) }, 80 * 80},
+		{"clamped", func(dc *Context) { dc.DrawRoundedRectangleCorners(10, 10, 80, 80, 80, 80, 80, 80) }, math.Pi * 40 * 40},
+		{"star", func(dc *Context) { dc.DrawStar(4, 50, 50, 40, 40*math.Sqrt2/2, 0) }, 80 * 80 / 2},
+		{"squircle", func(dc *Context) { dc.DrawSquircle(50, 50, 40) }, 4 * 40 * 40 * math.Pow(math.Gamma(1.25), 2) / math.Gamma(1.5)},
+	}
+	for _, c := range cases {
+		dc := NewContext(100, 100)
+		dc.SetRGB(0, 0, 0)
+		c.draw(dc)
+		dc.Fill()
+		if got := coverage(dc); math.Abs(got-c.area)/c.area > 0.02 {
+			t.Errorf("%s: expected area %.1f, got %.1f", c.name, c.area, got)
+		}
+	}
+}
+
+func TestDegeneratePolygons(t *testing.T) {
+	// too few sides draw nothing instead of panicking
+	for _, n := range []int{-3, -1, 0, 1, 2} {
+		dc := NewContext(100, 100)
+		dc.SetRGB(0, 0, 0)
+		dc.DrawRegularPolygon(n, 50, 50, 40, 0)
+		if n < 2 {
+			dc.DrawStar(n, 50, 50, 40, 20, 0)
+		}
+		dc.DrawRoundedRegularPolygon(n, 50, 50, 40, 0, 5)
+		dc.Fill()
+		if got := coverage(dc); got != 0 {
+			t.Errorf("n = %d: expected nothing drawn, got area %.1f", n, got)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package gg
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/golang/freetype/raster"
+	"golang.org/x/image/draw"
+)
+
+// RGBAF32 is an in-memory image whose At method returns color.RGBA64
+// values. Its pixels hold premultiplied float32 components in [0, 1] in
+// R, G, B, A order. If Linear is set, the color components are stored in
+// linear light instead of sRGB, so that drawing onto the image blends
+// colors gamma-correctly, while At and Set still deal in sRGB colors.
+//
+// RGBAF32 是一个内存图像，其 At 方法返回 color.RGBA64 值。
+// 其像素以 R、G、B、A 的顺序保存 [0, 1] 范围内预乘的 float32 分量。
+// 若设置了 Linear，颜色分量以线性光而非 sRGB 保存，从而在图像上绘制时以正确的伽马混合颜色，
+// 而 At 与 Set 仍使用 sRGB 颜色。
+type RGBAF32 struct {
+	// Pix holds the image's pixels, in R, G, B, A order. The pixel at
+	// (x, y) starts at Pix[(y-Rect.Min.Y)*Stride + (x-Rect.Min.X)*4].
+	//
+	// Pix 保存图像的像素，顺序为 R、G、B、A。
+	// 位于 (x, y) 的像素起始于 Pix[(y-Rect.Min.Y)*Stride + (x-Rect.Min.X)*4]。
+	Pix []float32
+	// Stride is the Pix stride (in elements) between vertically adjacent
+	// pixels.
+	//
+	// Stride 为垂直相邻像素之间 Pix 的跨度（以元素计）。
+	Stride int
+	// Rect is the image's bounds.
+	//
+	// Rect 为图像的边界。
+	Rect image.Rectangle
+	// Linear reports whether the color components are in linear light.
+	//
+	// Linear 表示颜色分量是否为线性光。
+	Linear bool
+}
+
+// NewRGBAF32 returns a new transparent RGBAF32 image with the given bounds,
+// storing linear light if linear is set.
+//
+// NewRGBAF32 返回具有给定边界的新透明 RGBAF32 图像，linear 为 true 时保存线性光。
+func NewRGBAF32(r image.Rectangle, linear bool) *RGBAF32 {
+	return &RGBAF32{
+		Pix:    make([]float32, 4*r.Dx()*r.Dy()),
+		Stride: 4 * r.Dx(),
+		Rect:   r,
+		Linear: linear,
+	}
+}
+
+func (p *RGBAF32) ColorModel() color.Model { return color.RGBA64Model }
+
+func (p *RGBAF32) Bounds() image.Rectangle { return p.Rect }
+
+func (p *RGBAF32) At(x, y int) color.Color {
+	return p.RGBA64At(x, y)
+}
+
+// RGBA64At returns the sRGB color of the pixel at (x, y).
+//
+// RGBA64At 返回位于 (x, y) 的像素的 sRGB 颜色。
+func (p *RGBAF32) RGBA64At(x, y int) color.RGBA64 {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return color.RGBA64{}
+	}
+	i := p.PixOffset(x, y)
+	return p.decode(p.Pix[i : i+4 : i+4])
+}
+
+// PixOffset returns the index of the first element of Pix that
+// corresponds to the pixel at (x, y).
+//
+// PixOffset 返回 Pix 中对应于 (x, y) 处像素的第一个元素的索引。
+func (p *RGBAF32) PixOffset(x, y int) int {
+	return (y-p.Rect.Min.Y)*p.Stride + (x-p.Rect.Min.X)*4
+}
+
+func (p *RGBAF32) Set(x, y int, c color.Color) {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return
+	}
+	i := p.PixOffset(x, y)
+	v := p.encode(c.RGBA())
+	copy(p.Pix[i:i+4], v[:])
+}
+
+// SetRGBA64 sets the pixel at (x, y) to the sRGB color c.
+//
+// SetRGBA64 将位于 (x, y) 的像素设置为 sRGB 颜色 c。
+func (p *RGBAF32) SetRGBA64(x, y int, c color.RGBA64) {
+	p.Set(x, y, c)
+}
+
+// ToRGBA64 converts the image to a 16-bit sRGB *image.RGBA64.
+//
+// ToRGBA64 将图像转换为 16 位 sRGB 的 *image.RGBA64。
+func (p *RGBAF32) ToRGBA64() *image.RGBA64 {
+	dst := image.NewRGBA64(p.Rect)
+	for y := p.Rect.Min.Y; y < p.Rect.Max.Y; y++ {
+		for x := p.Rect.Min.X; x < p.Rect.Max.X; x++ {
+			dst.SetRGBA64(x, y, p.RGBA64At(x, y))
+		}
+	}
+	return dst
+}
+
+// encode converts a premultiplied 16-bit sRGB color to the components
+// stored in p.
+func (p *RGBAF32) encode(r, g, b, a uint32) [4]float32 {
+	const m = 0xffff
+	if !p.Linear || a == 0 {
+		return [4]float32{float32(r) / m, float32(g) / m, float32(b) / m, float32(a) / m}
+	}
+	fa := float64(a)
+	lin := func(c uint32) float32 {
+		return float32(srgbToLinear(float64(c)/fa) * fa / m)
+	}
+	return [4]float32{lin(r), lin(g), lin(b), float32(a) / m}
+}
+
+// decode converts the components stored in p to a premultiplied 16-bit
+// sRGB color.
+func (p *RGBAF32) decode(v []float32) color.RGBA64 {
+	q := func(c float64) uint16 {
+		return uint16(max(0, min(1, c))*0xffff + 0.5)
+	}
+	a := max(0, min(1, float64(v[3])))
+	if !p.Linear || a == 0 {
+		ca := q(a)
+		return color.RGBA64{min(q(float64(v[0])), ca), min(q(float64(v[1])), ca), min(q(float64(v[2])), ca), ca}
+	}
+	srgb := func(c float32) uint16 {
+		return q(linearToSRGB(max(0, min(1, float64(c)/a))) * a)
+	}
+	return color.RGBA64{srgb(v[0]), srgb(v[1]), srgb(v[2]), q(a)}
+}
+
+// NewContextRGBA64 creates a new 16-bit image.RGBA64 with the specified
+// width and height and prepares a context for rendering onto that image.
+// Saving it as a PNG writes a 16-bit PNG.
+//
+// NewContextRGBA64 创建一个具有指定宽度和高度的新 16 位 image.RGBA64，
+// 并准备渲染到该图像上的上下文。将其保存为 PNG 时写入 16 位 PNG。
+func NewContextRGBA64(width, height int) *Context {
+	return NewContextForRGBA64(image.NewRGBA64(image.Rect(0, 0, width, height)))
+}
+
+// NewContextForRGBA64 prepares a context for rendering onto the specified
+// 16-bit image. No copy is made.
+//
+// NewContextForRGBA64 准备渲染到指定 16 位图像的上下文。没有复制。
+func NewContextForRGBA64(im *image.RGBA64) *Context {
+	return newContext(im)
+}
+
+// NewContextRGBAF32 creates a new float32 RGBAF32 image with the specified
+// width and height and prepares a context for rendering onto that image.
+// If linear is set, fills and strokes are blended in linear light. The
+// Image method and the PNG output convert the result to 16-bit sRGB.
+//
+// NewContextRGBAF32 创建一个具有指定宽度和高度的新 float32 RGBAF32 图像，
+// 并准备渲染到该图像上的上下文。若 linear 为 true，填充与描边在线性光中混合。
+// Image 方法与 PNG 输出会将结果转换为 16 位 sRGB。
+func NewContextRGBAF32(width, height int, linear bool) *Context {
+	return NewContextForRGBAF32(NewRGBAF32(image.Rect(0, 0, width, height), linear))
+}
+
+// NewContextForRGBAF32 prepares a context for rendering onto the specified
+// float32 image. No copy is made.
+//
+// NewContextForRGBAF32 准备渲染到指定 float32 图像的上下文。没有复制。
+func NewContextForRGBAF32(im *RGBAF32) *Context {
+	return newContext(im)
+}
+
+func (r *patternPainter) paintRGBA64(im *image.RGBA64, s raster.Span, x0, y int) {
+	const m = 1<<16 - 1
+	i0 := y*im.Stride + x0*8
+	i1 := i0 + (s.X1-s.X0)*8
+	for i, x := i0, x0; i < i1; i, x = i+8, x+1 {
+		ma := r.coverage(s.Alpha, x, y)
+		if ma == 0 {
+			continue
+		}
+		cr, cg, cb, ca := r.color(x, x0, y)
+		a := uint64(m - ca*ma/m)
+		for j, c := range [4]uint32{cr, cg, cb, ca} {
+			d := uint64(im.Pix[i+2*j])<<8 | uint64(im.Pix[i+2*j+1])
+			v := (d*a + uint64(c)*uint64(ma)) / m
+			im.Pix[i+2*j], im.Pix[i+2*j+1] = uint8(v>>8), uint8(v)
+		}
+	}
+}
+
+func (r *patternPainter) paintRGBAF32(im *RGBAF32, s raster.Span, x0, y int) {
+	const m = 1<<16 - 1
+	var (
+		last [4]uint32
+		c    [4]float32
+	)
+	i0 := y*im.Stride + x0*4
+	i1 := i0 + (s.X1-s.X0)*4
+	for i, x := i0, x0; i < i1; i, x = i+4, x+1 {
+		ma := r.coverage(s.Alpha, x, y)
+		if ma == 0 {
+			continue
+		}
+		cr, cg, cb, ca := r.color(x, x0, y)
+		// converting to linear light is slow, so reuse the last color
+		if last != [4]uint32{cr, cg, cb, ca} {
+			last = [4]uint32{cr, cg, cb, ca}
+			c = im.encode(cr, cg, cb, ca)
+		}
+		k := float32(ma) / m
+		a := 1 - c[3]*k
+		for j := range c {
+			im.Pix[i+j] = im.Pix[i+j]*a + c[j]*k
+		}
+	}
+}
+
+// translate returns im moved by d, sharing its pixels.
+func translate(im draw.Image, d image.Point) draw.Image {
+	if d == (image.Point{}) {
+		return im
+	}
+	switch im := im.(type) {
+	case *image.RGBA:
+		c := *im
+		c.Rect = c.Rect.Add(d)
+		return &c
+	case *image.RGBA64:
+		c := *im
+		c.Rect = c.Rect.Add(d)
+		return &c
+	case *image.NRGBA:
+		c := *im
+		c.Rect = c.Rect.Add(d)
+		return &c
+	case *image.NRGBA64:
+		c := *im
+		c.Rect = c.Rect.Add(d)
+		return &c
+	case *image.Gray:
+		c := *im
+		c.Rect = c.Rect.Add(d)
+		return &c
+	case *image.Gray16:
+		c := *im
+		c.Rect = c.Rect.Add(d)
+		return &c
+	case *image.Paletted:
+		c := *im
+		c.Rect = c.Rect.Add(d)
+		return &c
+	case *RGBAF32:
+		c := *im
+		c.Rect = c.Rect.Add(d)
+		return &c
+	case *translatedImage:
+		if d = d.Add(im.d); d == (image.Point{}) {
+			return im.Image
+		}
+		return &translatedImage{im.Image, d}
+	}
+	return &translatedImage{im, d}
+}
+
+// translatedImage is Image moved by d.
+type translatedImage struct {
+	draw.Image
+	d image.Point
+}
+
+func (t *translatedImage) Bounds() image.Rectangle {
+	return t.Image.Bounds().Add(t.d)
+}
+
+func (t *translatedImage) At(x, y int) color.Color {
+	return t.Image.At(x-t.d.X, y-t.d.Y)
+}
+
+func (t *translatedImage) Set(x, y int, c color.Color) {
+	t.Image.Set(x-t.d.X, y-t.d.Y, c)
+}
+
+// paintImage paints onto any draw.Image through its At and Set methods.
+func (r *patternPainter) paintImage(im draw.Image, s raster.Span, x0, y int) {
+	const m = 1<<16 - 1
+	b := im.Bounds()
+	for x := x0; x < x0+s.X1-s.X0; x++ {
+		ma := r.coverage(s.Alpha, x, y)
+		if ma == 0 {
+			continue
+		}
+		cr, cg, cb, ca := r.color(x, x0, y)
+		dr, dg, db, da := im.At(x+b.Min.X, y+b.Min.Y).RGBA()
+		a := m - ca*ma/m
+		im.Set(x+b.Min.X, y+b.Min.Y, color.RGBA64{
+			uint16((uint64(dr)*uint64(a) + uint64(cr)*uint64(ma)) / m),
+			uint16((uint64(dg)*uint64(a) + uint64(cg)*uint64(ma)) / m),
+			uint16((uint64(db)*uint64(a) + uint64(cb)*uint64(ma)) / m),
+			uint16((uint64(da)*uint64(a) + uint64(ca)*uint64(ma)) / m),
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package gg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/png"
+	"testing"
+)
+
+func TestContextRGBA64(t *testing.T) {
+	dc := NewContextRGBA64(1000, 10)
+	g := NewLinearGradient(0, 0, 1000, 0)
+	g.AddColorStop(0, color.Black)
+	g.AddColorStop(1, color.White)
+	dc.SetFillStyle(g)
+	dc.DrawRectangle(0, 0, 1000, 10)
+	dc.Fill()
+	im, ok := dc.Image().(*image.RGBA64)
+	if !ok {
+		t.Fatalf("expected *image.RGBA64, got %T", dc.Image())
+	}
+	levels := map[uint16]bool{}
+	for x := range 1000 {
+		levels[im.RGBA64At(x, 5).R] = true
+	}
+	// an 8-bit image has at most 256 levels
+	if len(levels) <= 256 {
+		t.Fatalf("expected more than 256 levels, got %d", len(levels))
+	}
+	var buf bytes.Buffer
+	if err := dc.EncodePNG(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := out.(*image.RGBA64); !ok {
+		t.Fatalf("expected a 16-bit PNG, got %T", out)
+	}
+}
+
+func TestRGBAF32(t *testing.T) {
+	for _, linear := range []bool{false, true} {
+		im := NewRGBAF32(image.Rect(-2, -2, 2, 2), linear)
+		for _, c := range []color.RGBA64{
+			{0, 0, 0, 0},
+			{0x1234, 0x5678, 0x9abc, 0xffff},
+			{0x0100, 0x2000, 0x7000, 0x8000},
+		} {
+			im.SetRGBA64(-1, 1, c)
+			if got := im.RGBA64At(-1, 1); !sameColor(got, c) {
+				t.Errorf("linear=%v: expected %v, got %v", linear, c, got)
+			}
+		}
+	}
+}
+
+func TestLinearBlending(t *testing.T) {
+	blend := func(dc *Context) color.Color {
+		dc.SetRGB(1, 1, 1)
+		dc.Clear()
+		dc.SetRGBA(0, 0, 0, 0.5)
+		dc.DrawRectangle(0, 0, 10, 10)
+		dc.Fill()
+		return dc.Image().At(5, 5)
+	}
+	if c := blend(NewContextRGBAF32(10, 10, false)); !sameColor(c, color.Gray{128}) {
+		t.Errorf("expected sRGB blending to give gray 128, got %v", c)
+	}
+	// half of the light of white is 188 in sRGB
+	if c := blend(NewContextRGBAF32(10, 10, true)); !sameColor(c, color.Gray{188}) {
+		t.Errorf("expected linear blending to give gray 188, got %v", c)
+	}
+}
+
+// drawScene draws shapes, a clipped gradient and masked text.
+func drawScene(dc *Context) {
+	dc.SetRGB(0.2, 0.4, 0.8)
+	dc.DrawCircle(30, 30, 20)
+	dc.Fill()
+	dc.DrawRectangle(10, 10, 40, 20)
+	dc.Clip()
+	g := NewLinearGradient(0, 0, 60, 0)
+	g.AddColorStop(0, color.RGBA{255, 0, 0, 255})
+	g.AddColorStop(1, color.RGBA{0, 255, 0, 255})
+	dc.SetFillStyle(g)
+	dc.DrawRectangle(0, 0, 60, 60)
+	dc.Fill()
+	dc.SetRGB(0, 0, 0)
+	dc.DrawString("gg", 20, 28)
+	dc.ResetClip()
+	dc.SetLineWidth(3)
+	dc.DrawLine(0, 55, 60, 45)
+	dc.Stroke()
+}
+
+func TestSubImageContext(t *testing.T) {
+	want := NewContext(60, 60)
+	drawScene(want)
+	atlas := image.NewRGBA(image.Rect(0, 0, 100, 100))
+	sub := atlas.SubImage(image.Rect(30, 20, 90, 80)).(*image.RGBA)
+	dc := NewContextForRGBA(sub)
+	drawScene(dc)
+	if b := dc.Image().Bounds(); b != sub.Rect {
+		t.Fatalf("expected bounds %v, got %v", sub.Rect, b)
+	}
+	for y := range 100 {
+		for x := range 100 {
+			var c color.RGBA
+			if p := image.Pt(x, y); p.In(sub.Rect) {
+				c = want.im.(*image.RGBA).RGBAAt(x-30, y-20)
+			}
+			if got := atlas.RGBAAt(x, y); got != c {
+				t.Fatalf("(%d, %d): expected %v, got %v", x, y, c, got)
+			}
+		}
+	}
+	checkHash(t, dc, want.String())
+}
+
+func TestDrawImageContext(t *testing.T) {
+	want := NewContext(60, 60)
+	drawScene(want)
+	r := image.Rect(-5, 7, 55, 67)
+	for _, im := range []draw.Image{
+		image.NewNRGBA(r),
+		image.NewGray(r),
+		image.NewPaletted(r, palette.WebSafe),
+	} {
+		dc := NewContextForDrawImage(im)
+		drawScene(dc)
+		model := im.ColorModel()
+		for y := range 60 {
+			for x := range 60 {
+				// colors are rounded to the model after every drawing, so
+				// compare fully covered pixels only
+				c := want.im.(*image.RGBA).RGBAAt(x, y)
+				if c.A != 255 || c != want.im.(*image.RGBA).RGBAAt(x+1, y) {
+					continue
+				}
+				if got, exp := im.At(x-5, y+7), model.Convert(c); !sameColor(got, exp) {
+					t.Fatalf("%T (%d, %d): expected %v, got %v", im, x, y, exp, got)
+				}
+			}
+		}
+	}
+}